@@ -0,0 +1,150 @@
+// Fixed-base scalar multiplication with a precomputed table.
+//
+// The scalar is recoded into 64 signed radix-16 digits in [-8, 8] and
+// multiplied like ref10's ge_scalarmult_base: the table holds
+// j * 256^i * B for i < 32 and 1 <= j <= 8, and every lookup reads all
+// eight entries of a row so the time does not depend on the scalar.
+
+package axlsign
+
+import "sync"
+
+// Precomputed affine point: (y + x, y - x, 2 * d * x * y).
+type precomp [3]gf
+
+var baseTable [32][8]precomp
+var baseTableOnce sync.Once
+
+func toPrecomp(r *precomp, p *[4]gf) {
+  var x, y, zi gf
+
+  inv25519(&zi, &p[2])
+  M(&x, &p[0], &zi)
+  M(&y, &p[1], &zi)
+
+  A(&r[0], &y, &x)
+  Z(&r[1], &y, &x)
+  M(&r[2], &x, &y)
+  M(&r[2], &r[2], &D2)
+}
+
+func initBaseTable() {
+  var p, q [4]gf
+
+  // Ed25519 base point B.
+  set25519(&p[0], &X)
+  set25519(&p[1], &Y)
+  set25519(&p[2], &gf1)
+  M(&p[3], &X, &Y)
+
+  for i := 0; i < 32; i++ {
+    q = p
+    for j := 0; j < 8; j++ {
+      toPrecomp(&baseTable[i][j], &q)
+      add(&q, &p)
+    }
+    // p = 256 * p
+    for j := 0; j < 8; j++ {
+      add(&p, &p)
+    }
+  }
+}
+
+// Adds the precomputed point q to p.
+func addPrecomp(p *[4]gf, q *precomp) {
+  var a, b, c, d, e, f, g, h gf
+
+  Z(&a, &p[1], &p[0])
+  M(&a, &a, &q[1])
+  A(&b, &p[0], &p[1])
+  M(&b, &b, &q[0])
+  M(&c, &p[3], &q[2])
+  A(&d, &p[2], &p[2])
+  Z(&e, &b, &a)
+  Z(&f, &d, &c)
+  A(&g, &d, &c)
+  A(&h, &b, &a)
+
+  M(&p[0], &e, &f)
+  M(&p[1], &h, &g)
+  M(&p[2], &g, &f)
+  M(&p[3], &e, &h)
+}
+
+// Sets t to b * 256^i * B in constant time, for b in [-8, 8].
+func selectPrecomp(t *precomp, i int, b int8) {
+  var u precomp
+  var neg = int( uint8(b) >> 7 )
+  var babs = int(b) - ((-neg & int(b)) << 1)
+
+  // Identity.
+  t[0] = gf1
+  t[1] = gf1
+  t[2] = gf0
+
+  for j := 0; j < 8; j++ {
+    u = baseTable[i][j]
+    var eq = int( (uint32(babs ^ (j + 1)) - 1) >> 31 )
+    sel25519(&t[0], &u[0], eq)
+    sel25519(&t[1], &u[1], eq)
+    sel25519(&t[2], &u[2], eq)
+  }
+
+  // Negate: swap y + x with y - x and negate 2 * d * x * y.
+  u[0] = t[1]
+  u[1] = t[0]
+  Z(&u[2], &gf0, &t[2])
+  sel25519(&t[0], &u[0], neg)
+  sel25519(&t[1], &u[1], neg)
+  sel25519(&t[2], &u[2], neg)
+}
+
+// Sets p = s * B. Only the first 32 bytes of s are used.
+func scalarbase(p *[4]gf, s []uint8) {
+  var a [64]uint8
+  var e [64]int8
+  var t precomp
+  var carry int8
+
+  baseTableOnce.Do(initBaseTable)
+
+  // B has order L, so reducing s first does not change the result and
+  // keeps the top digit within the table.
+  for i := 0; i < 32; i++ {
+    a[i] = s[i]
+  }
+  reduce(a[:])
+
+  for i := 0; i < 32; i++ {
+    e[2*i] = int8(a[i] & 15)
+    e[2*i+1] = int8(a[i] >> 4)
+  }
+
+  // Each e[i] is now between 0 and 15, make it between -8 and 7.
+  for i := 0; i < 63; i++ {
+    e[i] += carry
+    carry = (e[i] + 8) >> 4
+    e[i] -= carry << 4
+  }
+  e[63] += carry
+
+  set25519(&p[0], &gf0)
+  set25519(&p[1], &gf1)
+  set25519(&p[2], &gf1)
+  set25519(&p[3], &gf0)
+
+  for i := 1; i < 64; i += 2 {
+    selectPrecomp(&t, i/2, e[i])
+    addPrecomp(p, &t)
+  }
+
+  add(p, p)
+  add(p, p)
+  add(p, p)
+  add(p, p)
+
+  for i := 0; i < 64; i += 2 {
+    selectPrecomp(&t, i/2, e[i])
+    addPrecomp(p, &t)
+  }
+}
@@ -0,0 +1,183 @@
+// Batch signature verification.
+//
+// A batch of n signatures (R_i, S_i) on messages m_i by public keys A_i is
+// accepted when
+//
+//   8 * ((sum z_i * S_i) * B - sum (z_i * h_i) * A_i - sum z_i * R_i) = 0
+//
+// for random 128-bit z_i, with h_i = H(R_i || A_i || m_i). The combination
+// is computed with a Pippenger multi-scalar multiplication.
+//
+// The cofactorless equation of Verify cannot be batched soundly, so the
+// verdicts are those of VerifyWithOptions with the Cofactored policy:
+// keys, R and S must be canonical, small-order keys and R values are
+// rejected before batching, and the fallback uses the same policy. For
+// honestly generated signatures this agrees with Verify; it can only
+// disagree on signatures crafted with mixed-order components or S >= L.
+
+package axlsign
+
+import cryptorand "crypto/rand"
+import "crypto/sha512"
+
+// Sets r = (a * b + c) mod L, for 32-byte little-endian a, b and c.
+func mulAddModL(r []uint8, a []uint8, b []uint8, c []uint8) {
+  var x [64]int64
+
+  for i := 0; i < 32; i++ {
+    x[i] = int64(c[i])
+  }
+
+  for i := 0; i < 32; i++ {
+    for j := 0; j < 32; j++ {
+      x[i+j] += int64( a[i] ) * int64( b[j] )
+    }
+  }
+
+  modL(r, &x)
+}
+
+func isIdentity(p *[4]gf) bool {
+  return neq25519(&p[0], &gf0) == 0 && neq25519(&p[1], &p[2]) == 0
+}
+
+// Sets p = sum s[i] * q[i] with the bucket method. Not constant time.
+func multiscalarmult(p *[4]gf, s [][32]uint8, q [][4]gf) {
+  var c = 4
+  for n := len(q); n >= 32 && c < 10; n = n >> 1 {
+    c++
+  }
+  var buckets = make([][4]gf, (1 << uint(c)) - 1)
+  var sum, total [4]gf
+
+  set25519(&p[0], &gf0)
+  set25519(&p[1], &gf1)
+  set25519(&p[2], &gf1)
+  set25519(&p[3], &gf0)
+
+  for w := (256 + c - 1) / c - 1; w >= 0; w-- {
+    for i := 0; i < c; i++ {
+      add(p, p)
+    }
+
+    for k := 0; k < len(buckets); k++ {
+      buckets[k] = [4]gf{gf0, gf1, gf1, gf0}
+    }
+
+    for i := 0; i < len(q); i++ {
+      // Extract the c-bit digit of s[i] at bit w*c.
+      var d = 0
+      for b := 0; b < c; b++ {
+        var bit = w * c + b
+        if (bit < 256) {
+          d = d | (int( s[i][bit >> 3] >> uint(bit & 7) ) & 1) << uint(b)
+        }
+      }
+      if (d > 0) {
+        add(&buckets[d-1], &q[i])
+      }
+    }
+
+    // total = sum (k+1) * buckets[k]
+    sum = [4]gf{gf0, gf1, gf1, gf0}
+    total = sum
+    for k := len(buckets) - 1; k >= 0; k-- {
+      add(&sum, &buckets[k])
+      add(&total, &sum)
+    }
+    add(p, &total)
+  }
+}
+
+// VerifyBatch verifies n signatures at once. It returns whether all of them
+// are valid and, for each one, whether it is valid under the Cofactored
+// policy of VerifyWithOptions. When the batch check fails, every signature
+// is verified individually to find the bad ones.
+// If the slices have different lengths it returns false and nil.
+func VerifyBatch(pubs [][32]uint8, msgs [][]uint8, sigs [][64]uint8) (bool, []bool) {
+  var n = len(pubs)
+  if (len(msgs) != n || len(sigs) != n) {
+    return false, nil
+  }
+
+  var perItem = make([]bool, n)
+  var index = make([]int, 0, n)
+  var scalars = make([][32]uint8, 0, 2*n)
+  var points = make([][4]gf, 0, 2*n)
+  var sb [32]uint8
+  var z = make([]uint8, 16*n)
+
+  if _, err := cryptorand.Read(z); err != nil {
+    return verifyEach(pubs, msgs, sigs, perItem)
+  }
+
+  for i := 0; i < n; i++ {
+    var edpk = convertPublicKey(pubs[i][:])
+    var sig = sigs[i]
+    var negA, negR [4]gf
+    var h [64]uint8
+    var zi [32]uint8
+
+    // Restore sign bit from signature, then remove it from the copy.
+    edpk[31] = edpk[31] | ( sig[63] & 128)
+    sig[63] = sig[63] & 127
+
+    // The checks of verifyPolicy for the Cofactored policy.
+    if (checkCanonical25519(pubs[i][:]) != 0 || !isCanonicalScalar(sig[32:])) {
+      continue
+    }
+    if ( unpackneg(&negA, edpk[:]) != 0 || unpackneg(&negR, sig[:32]) != 0 ) {
+      continue
+    }
+    if (!isCanonicalPoint(edpk[:], &negA) || !isCanonicalPoint(sig[:32], &negR)) {
+      continue
+    }
+    if (isSmallOrder(&negA) || isSmallOrder(&negR)) {
+      continue
+    }
+
+    var hs = sha512.New()
+    hs.Write(sig[:32])
+    hs.Write(edpk[:])
+    hs.Write(msgs[i])
+    hs.Sum(h[:0])
+    reduce(h[:])
+
+    copy(zi[:16], z[16*i:16*i+16])
+
+    var zh, zero [32]uint8
+    mulAddModL(zh[:], zi[:], h[:32], zero[:])
+    mulAddModL(sb[:], zi[:], sig[32:], sb[:])
+
+    scalars = append(scalars, zh, zi)
+    points = append(points, negA, negR)
+    index = append(index, i)
+  }
+
+  var p, q [4]gf
+  multiscalarmult(&p, scalars, points)
+  scalarbase(&q, sb[:])
+  add(&p, &q)
+
+  add(&p, &p)
+  add(&p, &p)
+  add(&p, &p)
+
+  if (!isIdentity(&p)) {
+    return verifyEach(pubs, msgs, sigs, perItem)
+  }
+
+  for i := 0; i < len(index); i++ {
+    perItem[index[i]] = true
+  }
+  return len(index) == n, perItem
+}
+
+func verifyEach(pubs [][32]uint8, msgs [][]uint8, sigs [][64]uint8, perItem []bool) (bool, []bool) {
+  var allOK = true
+  for i := 0; i < len(pubs); i++ {
+    perItem[i] = VerifyWithOptions(pubs[i], msgs[i], sigs[i], VerifyOptions{Policy: Cofactored})
+    allOK = allOK && perItem[i]
+  }
+  return allOK, perItem
+}
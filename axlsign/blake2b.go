@@ -0,0 +1,98 @@
+// Unkeyed BLAKE2b (RFC 7693), used for the sealed box nonce.
+
+package axlsign
+
+var blake2bIV = [8]uint64 {
+  0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+  0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+var blake2bSigma = [12][16]uint8 {
+  { 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15 },
+  { 14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3 },
+  { 11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4 },
+  { 7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8 },
+  { 9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13 },
+  { 2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9 },
+  { 12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11 },
+  { 13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10 },
+  { 6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5 },
+  { 10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0 },
+  { 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15 },
+  { 14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3 },
+}
+
+func rotr64(x uint64, n uint) uint64 {
+  return (x >> n) | (x << (64 - n))
+}
+
+func blake2bG(v *[16]uint64, a int, b int, c int, d int, x uint64, y uint64) {
+  v[a] = v[a] + v[b] + x
+  v[d] = rotr64(v[d] ^ v[a], 32)
+  v[c] = v[c] + v[d]
+  v[b] = rotr64(v[b] ^ v[c], 24)
+  v[a] = v[a] + v[b] + y
+  v[d] = rotr64(v[d] ^ v[a], 16)
+  v[c] = v[c] + v[d]
+  v[b] = rotr64(v[b] ^ v[c], 63)
+}
+
+// Compresses the 128-byte block into h; t is the byte count so far.
+func blake2bCompress(h *[8]uint64, block []uint8, t uint64, last bool) {
+  var v [16]uint64
+  var m [16]uint64
+
+  for i := 0; i < 16; i++ {
+    for j := 7; j >= 0; j-- {
+      m[i] = (m[i] << 8) | uint64(block[8*i+j])
+    }
+  }
+
+  for i := 0; i < 8; i++ {
+    v[i] = h[i]
+    v[i+8] = blake2bIV[i]
+  }
+  v[12] ^= t
+  if (last) {
+    v[14] = ^v[14]
+  }
+
+  for i := 0; i < 12; i++ {
+    var s = &blake2bSigma[i]
+    blake2bG(&v, 0, 4, 8, 12, m[s[0]], m[s[1]])
+    blake2bG(&v, 1, 5, 9, 13, m[s[2]], m[s[3]])
+    blake2bG(&v, 2, 6, 10, 14, m[s[4]], m[s[5]])
+    blake2bG(&v, 3, 7, 11, 15, m[s[6]], m[s[7]])
+    blake2bG(&v, 0, 5, 10, 15, m[s[8]], m[s[9]])
+    blake2bG(&v, 1, 6, 11, 12, m[s[10]], m[s[11]])
+    blake2bG(&v, 2, 7, 8, 13, m[s[12]], m[s[13]])
+    blake2bG(&v, 3, 4, 9, 14, m[s[14]], m[s[15]])
+  }
+
+  for i := 0; i < 8; i++ {
+    h[i] ^= v[i] ^ v[i+8]
+  }
+}
+
+// Sets out to the unkeyed BLAKE2b hash of in, with len(out) between 1
+// and 64 bytes.
+func blake2b(out []uint8, in []uint8) {
+  var h = blake2bIV
+  var block [128]uint8
+  var t uint64 = 0
+
+  h[0] ^= 0x01010000 ^ uint64(len(out))
+
+  for (len(in) > 128) {
+    t += 128
+    blake2bCompress(&h, in[:128], t, false)
+    in = in[128:]
+  }
+  copy(block[:], in)
+  t += uint64(len(in))
+  blake2bCompress(&h, block[:], t, true)
+
+  for i := 0; i < len(out); i++ {
+    out[i] = uint8(h[i/8] >> (8 * uint(i%8)))
+  }
+}
@@ -0,0 +1,234 @@
+// Public-key authenticated encryption, compatible with NaCl crypto_box
+// (X25519 + HSalsa20 + XSalsa20-Poly1305) and libsodium crypto_box_easy.
+//
+// Ported from TweetNaCl. The sealed box is the 16-byte Poly1305 tag
+// followed by the ciphertext, as in libsodium's combined mode. Keys are
+// the same Curve25519 keys used for signing.
+
+package axlsign
+
+import "curve25519-go/internal/poly1305"
+
+// BoxOverhead is the number of bytes Seal adds to a message.
+const BoxOverhead = 16
+
+var sigma = []uint8("expand 32-byte k")
+
+func crypto_verify_16(x []uint8, xi int, y []uint8, yi int) int {
+  return vn(x,xi,y,yi,16)
+}
+
+func L32(x uint32, c uint) uint32 {
+  return (x << c) | (x >> (32 - c))
+}
+
+func ld32(x []uint8) uint32 {
+  var u = uint32(x[3])
+  u = (u << 8) | uint32(x[2])
+  u = (u << 8) | uint32(x[1])
+  return (u << 8) | uint32(x[0])
+}
+
+func st32(x []uint8, u uint32) {
+  for i := 0; i < 4; i++ {
+    x[i] = uint8(u)
+    u >>= 8
+  }
+}
+
+func core(out []uint8, in []uint8, k []uint8, c []uint8, h bool) {
+  var w, x, y [16]uint32
+  var t [4]uint32
+
+  for i := 0; i < 4; i++ {
+    x[5*i] = ld32(c[4*i:])
+    x[1+i] = ld32(k[4*i:])
+    x[6+i] = ld32(in[4*i:])
+    x[11+i] = ld32(k[16+4*i:])
+  }
+
+  y = x
+
+  for i := 0; i < 20; i++ {
+    for j := 0; j < 4; j++ {
+      for m := 0; m < 4; m++ {
+        t[m] = x[(5*j+4*m)%16]
+      }
+      t[1] ^= L32(t[0]+t[3], 7)
+      t[2] ^= L32(t[1]+t[0], 9)
+      t[3] ^= L32(t[2]+t[1], 13)
+      t[0] ^= L32(t[3]+t[2], 18)
+      for m := 0; m < 4; m++ {
+        w[4*j+(j+m)%4] = t[m]
+      }
+    }
+    x = w
+  }
+
+  if (h) {
+    for i := 0; i < 16; i++ {
+      x[i] += y[i]
+    }
+    for i := 0; i < 4; i++ {
+      x[5*i] -= ld32(c[4*i:])
+      x[6+i] -= ld32(in[4*i:])
+    }
+    for i := 0; i < 4; i++ {
+      st32(out[4*i:], x[5*i])
+      st32(out[16+4*i:], x[6+i])
+    }
+  } else {
+    for i := 0; i < 16; i++ {
+      st32(out[4*i:], x[i] + y[i])
+    }
+  }
+}
+
+func crypto_core_salsa20(out []uint8, inp []uint8, k []uint8, c []uint8) {
+  core(out, inp, k, c, false)
+}
+
+func crypto_core_hsalsa20(out []uint8, inp []uint8, k []uint8, c []uint8) {
+  core(out, inp, k, c, true)
+}
+
+// Sets c to m xor the Salsa20 stream for nonce n and key k; m may be nil
+// for the bare stream.
+func crypto_stream_salsa20_xor(c []uint8, m []uint8, b int, n []uint8, k []uint8) {
+  var z [16]uint8
+  var x [64]uint8
+  var ci, mi = 0, 0
+
+  for i := 0; i < 8; i++ {
+    z[i] = n[i]
+  }
+  for (b >= 64) {
+    crypto_core_salsa20(x[:], z[:], k, sigma)
+    for i := 0; i < 64; i++ {
+      if (m != nil) {
+        c[ci+i] = m[mi+i] ^ x[i]
+      } else {
+        c[ci+i] = x[i]
+      }
+    }
+    var u uint32 = 1
+    for i := 8; i < 16; i++ {
+      u = u + uint32(z[i])
+      z[i] = uint8(u)
+      u >>= 8
+    }
+    b -= 64
+    ci += 64
+    mi += 64
+  }
+  if (b > 0) {
+    crypto_core_salsa20(x[:], z[:], k, sigma)
+    for i := 0; i < b; i++ {
+      if (m != nil) {
+        c[ci+i] = m[mi+i] ^ x[i]
+      } else {
+        c[ci+i] = x[i]
+      }
+    }
+  }
+}
+
+func crypto_stream(c []uint8, d int, n []uint8, k []uint8) {
+  var s [32]uint8
+  crypto_core_hsalsa20(s[:], n, k, sigma)
+  crypto_stream_salsa20_xor(c, nil, d, n[16:], s[:])
+}
+
+func crypto_stream_xor(c []uint8, m []uint8, d int, n []uint8, k []uint8) {
+  var s [32]uint8
+  crypto_core_hsalsa20(s[:], n, k, sigma)
+  crypto_stream_salsa20_xor(c, m, d, n[16:], s[:])
+}
+
+func crypto_onetimeauth(out []uint8, m []uint8, n int, k []uint8) {
+  poly1305.Sum(out, m[:n], k)
+}
+
+func crypto_onetimeauth_verify(h []uint8, m []uint8, n int, k []uint8) int {
+  var x [16]uint8
+  crypto_onetimeauth(x[:], m, n, k)
+  return crypto_verify_16(h, 0, x[:], 0)
+}
+
+// Encrypts the d bytes of m, which start with 32 zero bytes, into c. The
+// first 16 bytes of c are zero and the next 16 hold the tag.
+func crypto_secretbox(c []uint8, m []uint8, d int, n []uint8, k []uint8) int {
+  if (d < 32) {
+    return -1
+  }
+  crypto_stream_xor(c, m, d, n, k)
+  crypto_onetimeauth(c[16:], c[32:], d - 32, c)
+  for i := 0; i < 16; i++ {
+    c[i] = 0
+  }
+  return 0
+}
+
+func crypto_secretbox_open(m []uint8, c []uint8, d int, n []uint8, k []uint8) int {
+  var x [32]uint8
+  if (d < 32) {
+    return -1
+  }
+  crypto_stream(x[:], 32, n, k)
+  if (crypto_onetimeauth_verify(c[16:], c[32:], d - 32, x[:]) != 0) {
+    return -1
+  }
+  crypto_stream_xor(m, c, d, n, k)
+  for i := 0; i < 32; i++ {
+    m[i] = 0
+  }
+  return 0
+}
+
+func crypto_box_beforenm(k []uint8, y []uint8, x []uint8) {
+  var s [32]uint8
+  var zero [16]uint8
+  crypto_scalarmult(s[:], x, y)
+  crypto_core_hsalsa20(k, zero[:], s[:], sigma)
+}
+
+// Appends to out the secretbox of msg under the shared key k.
+func secretboxSeal(out []uint8, msg []uint8, nonce []uint8, k []uint8) []uint8 {
+  var m = make([]uint8, 32 + len(msg))
+  var c = make([]uint8, 32 + len(msg))
+  copy(m[32:], msg)
+  crypto_secretbox(c, m, len(m), nonce, k)
+  return append(out, c[16:]...)
+}
+
+// Appends to out the opening of box under the shared key k.
+func secretboxOpen(out []uint8, box []uint8, nonce []uint8, k []uint8) ([]uint8, bool) {
+  if (len(box) < BoxOverhead) {
+    return nil, false
+  }
+  var c = make([]uint8, 16 + len(box))
+  var m = make([]uint8, 16 + len(box))
+  copy(c[16:], box)
+  if (crypto_secretbox_open(m, c, len(c), nonce, k) != 0) {
+    return nil, false
+  }
+  return append(out, m[32:]...), true
+}
+
+// Seal appends to out the encryption of msg for peerPub, authenticated by
+// priv, and returns the resulting slice. The nonce must be unique for each
+// message between the same pair of keys.
+func Seal(out []uint8, msg []uint8, nonce *[24]uint8, peerPub PublicKey, priv PrivateKey) []uint8 {
+  var k [32]uint8
+  crypto_box_beforenm(k[:], peerPub[:], priv[:])
+  return secretboxSeal(out, msg, nonce[:], k[:])
+}
+
+// Open authenticates and decrypts a box produced by Seal from peerPub to
+// priv, appends the message to out and returns the resulting slice. It
+// returns false if the box is not authentic.
+func Open(out []uint8, box []uint8, nonce *[24]uint8, peerPub PublicKey, priv PrivateKey) ([]uint8, bool) {
+  var k [32]uint8
+  crypto_box_beforenm(k[:], peerPub[:], priv[:])
+  return secretboxOpen(out, box, nonce[:], k[:])
+}
@@ -0,0 +1,84 @@
+// Checked X25519 key agreement, following RFC 7748 section 6.1.
+//
+// SharedKey returns the raw output of the scalar multiplication. A peer
+// sending one of the small-order points below forces an all-zero or
+// otherwise known shared key regardless of our private key; ECDH rejects
+// those keys and any all-zero result.
+
+package axlsign
+
+// Encodings of the u-coordinates of small-order points, including the
+// non-canonical encodings p, p + 1 and p - 1 of 0, 1 and -1. The top bit is
+// ignored when comparing, as it is by crypto_scalarmult.
+var lowOrderPoints = [7][32]uint8 {
+  // 0 (order 4)
+  { 0 },
+  // 1 (order 1)
+  { 1 },
+  // 325606250916557431795983626356110631294008115727848805560023387167927233504 (order 8)
+  { 0xe0, 0xeb, 0x7a, 0x7c, 0x3b, 0x41, 0xb8, 0xae, 0x16, 0x56, 0xe3, 0xfa,
+    0xf1, 0x9f, 0xc4, 0x6a, 0xda, 0x09, 0x8d, 0xeb, 0x9c, 0x32, 0xb1, 0xfd,
+    0x86, 0x62, 0x05, 0x16, 0x5f, 0x49, 0xb8, 0x00 },
+  // 39382357235489614581723060781553021112529911719440698176882885853963445705823 (order 8)
+  { 0x5f, 0x9c, 0x95, 0xbc, 0xa3, 0x50, 0x8c, 0x24, 0xb1, 0xd0, 0xb1, 0x55,
+    0x9c, 0x83, 0xef, 0x5b, 0x04, 0x44, 0x5c, 0xc4, 0x58, 0x1c, 0x8e, 0x86,
+    0xd8, 0x22, 0x4e, 0xdd, 0xd0, 0x9f, 0x11, 0x57 },
+  // p - 1 (order 2)
+  { 0xec, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+    0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+    0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f },
+  // p (= 0)
+  { 0xed, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+    0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+    0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f },
+  // p + 1 (= 1)
+  { 0xee, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+    0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+    0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f },
+}
+
+// ECDHOptions are the options of ECDHWithOptions.
+type ECDHOptions struct {
+  // RejectNonCanonical also rejects public keys that are not the
+  // canonical encoding of a u-coordinate: u >= p or the top bit set.
+  RejectNonCanonical bool
+}
+
+// Returns true if the 32-byte u is in lowOrderPoints, ignoring the top bit.
+func isLowOrder25519(u []uint8) bool {
+  var t [32]uint8
+  copy(t[:], u[:32])
+  t[31] = t[31] & 127
+
+  var found = 0
+  for i := 0; i < len(lowOrderPoints); i++ {
+    found = found | ( crypto_verify_32(t[:], 0, lowOrderPoints[i][:], 0) + 1 )
+  }
+  return found != 0
+}
+
+// ECDH returns the X25519 shared key between secretKey and the peer's
+// publicKey. It returns ErrLowOrderPoint if publicKey is a small-order
+// point or the result is all zero.
+func ECDH(secretKey []uint8, publicKey []uint8) ([]uint8, error) {
+  return ECDHWithOptions(secretKey, publicKey, ECDHOptions{})
+}
+
+// ECDHWithOptions is like ECDH with the checks selected by opts.
+func ECDHWithOptions(secretKey []uint8, publicKey []uint8, opts ECDHOptions) ([]uint8, error) {
+  if (len(secretKey) != 32 || len(publicKey) != 32) {
+    return nil, ErrInvalidKeySize
+  }
+  if (opts.RejectNonCanonical && checkCanonical25519(publicKey) != 0) {
+    return nil, ErrNonCanonicalKey
+  }
+  if (isLowOrder25519(publicKey)) {
+    return nil, ErrLowOrderPoint
+  }
+
+  var sharedKey = SharedKey(secretKey, publicKey)
+  if (isZero32(sharedKey)) {
+    return nil, ErrLowOrderPoint
+  }
+  return sharedKey, nil
+}
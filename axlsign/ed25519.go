@@ -0,0 +1,158 @@
+// RFC 8032 Ed25519, Ed25519ctx and Ed25519ph.
+//
+// These use standard Ed25519 keys: a 32-byte seed is expanded with SHA-512
+// into the secret scalar and the nonce prefix, and the public key is the
+// Edwards point encoding. They share the Edwards arithmetic with axlsign but
+// not the keys, which are not Curve25519 keys.
+
+package axlsign
+
+import "crypto/sha512"
+import "errors"
+
+// Ed25519PublicKey is a 32-byte RFC 8032 public key.
+type Ed25519PublicKey [32]uint8
+
+// Ed25519PrivateKey is a 64-byte RFC 8032 private key: seed || public key.
+type Ed25519PrivateKey [64]uint8
+
+var errContextTooLong = errors.New("axlsign: context too long")
+var errEmptyContext = errors.New("axlsign: empty context")
+
+var dom2Prefix = []uint8("SigEd25519 no Ed25519 collisions")
+
+// dom2(phflag, ctx)
+func dom2(phflag uint8, ctx []uint8) []uint8 {
+  var dom = make([]uint8, 0, len(dom2Prefix) + 2 + len(ctx))
+  dom = append(dom, dom2Prefix...)
+  dom = append(dom, phflag, uint8(len(ctx)))
+  return append(dom, ctx...)
+}
+
+// NewEd25519KeyFromSeed returns the private key for the 32-byte seed.
+func NewEd25519KeyFromSeed(seed [32]uint8) Ed25519PrivateKey {
+  var priv Ed25519PrivateKey
+  var h [64]uint8
+  var p [4]gf
+
+  h = sha512.Sum512(seed[:])
+  h[0] = h[0] & 248
+  h[31] = h[31] & 127
+  h[31] = h[31] | 64
+
+  scalarbase(&p, h[:32])
+
+  copy(priv[:32], seed[:])
+  pack(priv[32:], &p)
+  return priv
+}
+
+// Seed returns the seed priv was generated from.
+func (priv Ed25519PrivateKey) Seed() [32]uint8 {
+  var seed [32]uint8
+  copy(seed[:], priv[:32])
+  return seed
+}
+
+// Public returns the public key corresponding to priv.
+func (priv Ed25519PrivateKey) Public() Ed25519PublicKey {
+  var pub Ed25519PublicKey
+  copy(pub[:], priv[32:])
+  return pub
+}
+
+func ed25519Sign(sig []uint8, priv *Ed25519PrivateKey, dom []uint8, m []uint8) {
+  var h [64]uint8
+  var edsk [64]uint8
+  var r [64]uint8
+
+  h = sha512.Sum512(priv[:32])
+  h[0] = h[0] & 248
+  h[31] = h[31] & 127
+  h[31] = h[31] | 64
+
+  copy(edsk[:32], h[:32])
+  copy(edsk[32:], priv[32:])
+
+  // r = SHA-512(dom || prefix || M)
+  var hs = sha512.New()
+  hs.Write(dom)
+  hs.Write(h[32:])
+  hs.Write(m)
+  hs.Sum(r[:0])
+
+  crypto_sign_finish(sig, r[:], dom, m, edsk[:])
+}
+
+func ed25519Verify(pub *Ed25519PublicKey, dom []uint8, m []uint8, sig *Signature) bool {
+  if (!isCanonicalScalar(sig[32:])) {
+    return false
+  }
+  return crypto_sign_open(sig[:], dom, m, pub[:]) == 0
+}
+
+// Returns true if the 32-byte little-endian s is below L.
+func isCanonicalScalar(s []uint8) bool {
+  var c = 0
+  var n = 1
+  for i := 31; i >= 0; i-- {
+    c = c | ( (int(s[i]) - int(L[i])) >> 8 ) & n
+    n = n & ((((int(s[i]) ^ int(L[i])) - 1) >> 8))
+  }
+  return c != 0
+}
+
+// SignEd25519 returns the RFC 8032 Ed25519 signature of msg.
+func SignEd25519(priv Ed25519PrivateKey, msg []uint8) Signature {
+  var sig Signature
+  ed25519Sign(sig[:], &priv, nil, msg)
+  return sig
+}
+
+// VerifyEd25519 reports whether sig is a valid Ed25519 signature of msg.
+func VerifyEd25519(pub Ed25519PublicKey, msg []uint8, sig Signature) bool {
+  return ed25519Verify(&pub, nil, msg, &sig)
+}
+
+// SignEd25519ctx returns the Ed25519ctx signature of msg with the given
+// context, which must be 1 to 255 bytes long.
+func SignEd25519ctx(priv Ed25519PrivateKey, msg []uint8, ctx []uint8) (Signature, error) {
+  var sig Signature
+  if (len(ctx) == 0) {
+    return sig, errEmptyContext
+  }
+  if (len(ctx) > 255) {
+    return sig, errContextTooLong
+  }
+  ed25519Sign(sig[:], &priv, dom2(0, ctx), msg)
+  return sig, nil
+}
+
+// VerifyEd25519ctx reports whether sig is a valid Ed25519ctx signature of
+// msg with the given context.
+func VerifyEd25519ctx(pub Ed25519PublicKey, msg []uint8, sig Signature, ctx []uint8) bool {
+  if (len(ctx) == 0 || len(ctx) > 255) {
+    return false
+  }
+  return ed25519Verify(&pub, dom2(0, ctx), msg, &sig)
+}
+
+// SignEd25519ph returns the Ed25519ph signature of a message given its
+// SHA-512 digest. The context may be empty and at most 255 bytes long.
+func SignEd25519ph(priv Ed25519PrivateKey, digest [64]uint8, ctx []uint8) (Signature, error) {
+  var sig Signature
+  if (len(ctx) > 255) {
+    return sig, errContextTooLong
+  }
+  ed25519Sign(sig[:], &priv, dom2(1, ctx), digest[:])
+  return sig, nil
+}
+
+// VerifyEd25519ph reports whether sig is a valid Ed25519ph signature of the
+// message with the given SHA-512 digest and context.
+func VerifyEd25519ph(pub Ed25519PublicKey, digest [64]uint8, sig Signature, ctx []uint8) bool {
+  if (len(ctx) > 255) {
+    return false
+  }
+  return ed25519Verify(&pub, dom2(1, ctx), digest[:], &sig)
+}
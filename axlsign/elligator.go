@@ -0,0 +1,123 @@
+// Elligator 2 representatives of Curve25519 public keys.
+//
+// A representative is a 32-byte string that maps to a public key and is
+// indistinguishable from uniform random bytes, so a key exchange does not
+// reveal itself on the wire. The conventions are those of Monocypher and
+// obfs4: the map uses the non-square 2, the representative is at most
+// (p-1)/2 and its two top bits are random padding, ignored by the map.
+//
+// Only half of the points have a representative, and the public keys of
+// ordinary private keys are all in the prime-order subgroup, which would
+// give them away. GenerateRepresentableKey therefore adds a random
+// low-order component to the public key. Clamped private keys are
+// multiples of the cofactor, so SharedKey gives the same result with
+// either public key.
+
+package axlsign
+
+import "io"
+
+// Representative is the Elligator 2 representative of a public key.
+type Representative [32]uint8
+
+// Encoding of a point of order 8.
+var order8Point = [32]uint8 { 0xc7, 0x17, 0x6a, 0x70, 0x3d, 0x4d, 0xd8, 0x4f,
+                              0xba, 0x3c, 0x0b, 0x76, 0x0d, 0x10, 0x67, 0x0f,
+                              0x2a, 0x20, 0x53, 0xfa, 0x2c, 0x39, 0xcc, 0xc6,
+                              0x4e, 0xc7, 0xfd, 0x77, 0x92, 0xac, 0x03, 0x7a }
+
+// Inverse map: sets r to the representative of the u-coordinate u, using
+// bit 0 of tweak in place of the sign of v. Returns 0 if u has none.
+func elligator2Rev(r *gf, u *gf, tweak uint8) int {
+  var t1, t2, t3 gf
+
+  // t3 = 1 / sqrt(-2 * u * (u + A))
+  A(&t2, u, &_486662)
+  M(&t3, u, &t2)
+  A(&t3, &t3, &t3)
+  Z(&t3, &gf0, &t3)
+  var isSquare = sqrtRatioM1(&t3, &gf1, &t3)
+
+  // r = sqrt(-u / (2 * (u + A))) or sqrt(-(u + A) / (2 * u)), at most
+  // (p-1)/2, that is with 2 * r even.
+  t1 = *u
+  sel25519(&t1, &t2, int(tweak & 1))
+  M(&t3, &t1, &t3)
+  A(&t1, &t3, &t3)
+  Z(&t2, &gf0, &t3)
+  sel25519(&t3, &t2, par25519(&t1))
+  *r = t3
+  return isSquare
+}
+
+// GenerateRepresentableKey generates a key pair whose public key has an
+// Elligator 2 representative, reading 33 bytes from rand for each try.
+// The public key is not priv.Public(): it has an extra low-order
+// component, which SharedKey ignores.
+func GenerateRepresentableKey(rand io.Reader) (PublicKey, PrivateKey, Representative, error) {
+  var seed [33]uint8
+  var p, q [4]gf
+  var a, b, u gf
+  var small [32]uint8
+
+  for {
+    if _, err := io.ReadFull(rand, seed[:]); err != nil {
+      return PublicKey{}, PrivateKey{}, Representative{}, err
+    }
+
+    var priv PrivateKey
+    copy(priv[:], seed[:32])
+    priv[0] &= 248
+    priv[31] = (priv[31] & 127) | 64
+
+    // P = priv * B + (seed[0] & 7) * T, with T of order 8.
+    scalarbase(&p, priv[:])
+    unpack(&q, order8Point[:])
+    small[0] = seed[0] & 7
+    var t [4]gf
+    scalarmult(&t, &q, small[:])
+    add(&p, &t)
+
+    // u = (Z + Y) / (Z - Y)
+    A(&a, &p[2], &p[1])
+    Z(&b, &p[2], &p[1])
+    inv25519(&b, &b)
+    M(&u, &a, &b)
+
+    var pub PublicKey
+    pack25519(pub[:], &u)
+    if rep, ok := PublicKeyToRepresentative(pub, seed[32]); ok {
+      return pub, priv, rep, nil
+    }
+  }
+}
+
+// PublicKeyToRepresentative returns a representative of pub, like
+// Monocypher's crypto_elligator_rev: bit 0 of tweak chooses between the
+// two candidates and its two top bits are the padding of the result. It
+// returns false if pub has no representative.
+func PublicKeyToRepresentative(pub PublicKey, tweak uint8) (Representative, bool) {
+  var u, r gf
+  var rep Representative
+
+  unpack25519(&u, pub[:])
+  if (elligator2Rev(&r, &u, tweak) == 0) {
+    return rep, false
+  }
+  pack25519(rep[:], &r)
+  rep[31] |= tweak & 0xc0
+  return rep, true
+}
+
+// RepresentativeToPublicKey returns the public key that rep represents.
+// Every 32-byte string maps to some public key.
+func RepresentativeToPublicKey(rep Representative) PublicKey {
+  var r, u gf
+  var pub PublicKey
+
+  rep[31] &= 63
+  unpack25519(&r, rep[:])
+  elligator2(&u, &r)
+  pack25519(pub[:], &u)
+  return pub
+}
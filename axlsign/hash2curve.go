@@ -0,0 +1,259 @@
+// Hashing to edwards25519 and curve25519, as specified in RFC 9380:
+// https://www.rfc-editor.org/rfc/rfc9380
+//
+// The suites are edwards25519_XMD:SHA-512_ELL2_RO_ and _NU_, and
+// curve25519_XMD:SHA-512_ELL2_RO_ and _NU_. The RO (random oracle) suites
+// hash to two field elements and add the two mapped points, the NU
+// (nonuniform) suites map a single one and are cheaper but their output
+// is distinguishable from random points. Both clear the cofactor, so the
+// points are in the prime-order subgroup.
+//
+// The curve25519 suites give the same points as the edwards25519 ones,
+// through the birational map between the two curves.
+
+package axlsign
+
+import "crypto"
+import "errors"
+
+var errEmptyDST = errors.New("axlsign: empty domain separation tag")
+var errXMDLength = errors.New("axlsign: invalid expand_message_xmd length")
+
+// sqrt(-486664), with sgn0 = 0
+var sqrtM486664 = gf{0x7e06, 0xff45, 0x04aa, 0xcc6e,
+                     0x1a82, 0x4b7d, 0xd3d1, 0xc5a1,
+                     0x4f7e, 0x03fc, 0x08dc, 0xd27b,
+                     0x06bb, 0x60a0, 0xedf4, 0x0f26}
+
+// 2^256 mod p
+var _38 = gf{38}
+
+// ExpandMessageXMD returns length uniform bytes derived from msg and the
+// domain separation tag dst with the hash h, per RFC 9380 section 5.3.1.
+// Tags longer than 255 bytes are first hashed as the RFC specifies.
+func ExpandMessageXMD(h crypto.Hash, msg []uint8, dst []uint8, length int) ([]uint8, error) {
+  if (!h.Available()) {
+    return nil, errUnsupportedHash
+  }
+  if (len(dst) == 0) {
+    return nil, errEmptyDST
+  }
+
+  var H = h.New()
+  var bLen = H.Size()
+  var ell = (length + bLen - 1) / bLen
+  if (length <= 0 || ell > 255 || length > 65535) {
+    return nil, errXMDLength
+  }
+
+  if (len(dst) > 255) {
+    H.Write([]uint8("H2C-OVERSIZE-DST-"))
+    H.Write(dst)
+    dst = H.Sum(nil)
+    H.Reset()
+  }
+  var dstPrime = append(append([]uint8{}, dst...), uint8(len(dst)))
+
+  // b_0 = H(Z_pad || msg || I2OSP(len_in_bytes, 2) || I2OSP(0, 1) || DST_prime)
+  H.Write(make([]uint8, H.BlockSize()))
+  H.Write(msg)
+  H.Write([]uint8{ uint8(length >> 8), uint8(length), 0 })
+  H.Write(dstPrime)
+  var b0 = H.Sum(nil)
+
+  // b_i = H(strxor(b_0, b_(i-1)) || I2OSP(i, 1) || DST_prime)
+  var out = make([]uint8, 0, ell * bLen)
+  var bi = make([]uint8, bLen)
+  for i := 1; i <= ell; i++ {
+    for j := 0; j < bLen; j++ {
+      bi[j] ^= b0[j]
+    }
+    H.Reset()
+    H.Write(bi)
+    H.Write([]uint8{ uint8(i) })
+    H.Write(dstPrime)
+    bi = H.Sum(bi[:0])
+    out = append(out, bi...)
+  }
+  return out[:length], nil
+}
+
+// Sets o to the 48-byte big-endian integer b reduced mod p.
+func fromBytes48(o *gf, b []uint8) {
+  var lo, hi [32]uint8
+  var t gf
+
+  for i := 0; i < 32; i++ {
+    lo[i] = b[47-i]
+  }
+  for i := 0; i < 16; i++ {
+    hi[i] = b[15-i]
+  }
+
+  // lo mod 2^255 + 19 * bit 255 of lo + 38 * hi
+  unpack25519(o, lo[:])
+  t = gf{ 19 * int64(lo[31] >> 7) }
+  A(o, o, &t)
+  unpack25519(&t, hi[:])
+  M(&t, &t, &_38)
+  A(o, o, &t)
+}
+
+// hash_to_field(msg, len(u)) for p = 2^255 - 19, with L = 48.
+func hashToField25519(u []gf, msg []uint8, dst []uint8) error {
+  var b, err = ExpandMessageXMD(crypto.SHA512, msg, dst, 48 * len(u))
+  if (err != nil) {
+    return err
+  }
+  for i := range u {
+    fromBytes48(&u[i], b[48*i:48*i+48])
+  }
+  return nil
+}
+
+// Sets o = x^3 + A * x^2 + x.
+func curve25519RHS(o *gf, x *gf) {
+  var t gf
+  A(&t, x, &_486662)
+  M(&t, &t, x)
+  A(&t, &t, &gf1)
+  M(o, &t, x)
+}
+
+// map_to_curve_elligator2_curve25519: sets (s, t) to the Montgomery point
+// for the field element u.
+func mapToCurveElligator2(s *gf, t *gf, u *gf) {
+  var x1, x2, y1, y2, g gf
+
+  // x1 = -A / (1 + 2 * u^2), x2 = -x1 - A
+  S(&x1, u)
+  A(&x1, &x1, &x1)
+  A(&x1, &x1, &gf1)
+  inv25519(&x1, &x1)
+  M(&x1, &x1, &_486662)
+  Z(&x1, &gf0, &x1)
+  Z(&x2, &gf0, &x1)
+  Z(&x2, &x2, &_486662)
+
+  // If g(x1) is square, (x1, y1) with sgn0(y1) = 1, otherwise (x2, y2)
+  // with sgn0(y2) = 0. sqrtRatioM1 returns the root with sgn0 = 0.
+  curve25519RHS(&g, &x1)
+  var e1 = sqrtRatioM1(&y1, &g, &gf1)
+  Z(&y1, &gf0, &y1)
+  curve25519RHS(&g, &x2)
+  sqrtRatioM1(&y2, &g, &gf1)
+
+  sel25519(&x2, &x1, e1)
+  sel25519(&y2, &y1, e1)
+  *s = x2
+  *t = y2
+}
+
+// Sets p to the edwards25519 point for the Montgomery point (s, t):
+// x = sqrt(-486664) * s / t, y = (s - 1) / (s + 1), or the identity when
+// t = 0 or s = -1.
+func montgomeryToEdwards(p *[4]gf, s *gf, t *gf) {
+  var sp1, sm1, c gf
+  var q [4]gf
+
+  A(&sp1, s, &gf1)
+  Z(&sm1, s, &gf1)
+  M(&c, &sqrtM486664, s)
+
+  M(&q[0], &c, &sp1)
+  M(&q[1], &sm1, t)
+  M(&q[2], t, &sp1)
+  M(&q[3], &c, &sm1)
+
+  set25519(&p[0], &gf0)
+  set25519(&p[1], &gf1)
+  set25519(&p[2], &gf1)
+  set25519(&p[3], &gf0)
+  cswap(p, &q, 1 - eq25519(&q[2], &gf0))
+}
+
+// Sets p to the edwards25519 point of msg, with two field elements for
+// the RO suites and one for the NU suites.
+func hashToEdwards(p *[4]gf, msg []uint8, dst []uint8, ro bool) error {
+  var u [2]gf
+  var s, t gf
+  var n = 1
+  if (ro) {
+    n = 2
+  }
+  if err := hashToField25519(u[:n], msg, dst); err != nil {
+    return err
+  }
+
+  mapToCurveElligator2(&s, &t, &u[0])
+  montgomeryToEdwards(p, &s, &t)
+  if (ro) {
+    var q [4]gf
+    mapToCurveElligator2(&s, &t, &u[1])
+    montgomeryToEdwards(&q, &s, &t)
+    add(p, &q)
+  }
+
+  // clear_cofactor: h_eff = 8
+  add(p, p)
+  add(p, p)
+  add(p, p)
+  return nil
+}
+
+// Returns the u-coordinate (Z + Y) / (Z - Y) of an Edwards point.
+func edwardsToMontgomery(p *[4]gf) PublicKey {
+  var a, b gf
+  var pub PublicKey
+  A(&a, &p[2], &p[1])
+  Z(&b, &p[2], &p[1])
+  inv25519(&b, &b)
+  M(&a, &a, &b)
+  pack25519(pub[:], &a)
+  return pub
+}
+
+// HashToEdwards25519 hashes msg to an edwards25519 point with the suite
+// edwards25519_XMD:SHA-512_ELL2_RO_ and returns its 32-byte encoding. dst
+// is the domain separation tag of the application and must not be empty.
+func HashToEdwards25519(msg []uint8, dst []uint8) ([32]uint8, error) {
+  var p [4]gf
+  var out [32]uint8
+  if err := hashToEdwards(&p, msg, dst, true); err != nil {
+    return out, err
+  }
+  pack(out[:], &p)
+  return out, nil
+}
+
+// EncodeToEdwards25519 is like HashToEdwards25519 with the suite
+// edwards25519_XMD:SHA-512_ELL2_NU_.
+func EncodeToEdwards25519(msg []uint8, dst []uint8) ([32]uint8, error) {
+  var p [4]gf
+  var out [32]uint8
+  if err := hashToEdwards(&p, msg, dst, false); err != nil {
+    return out, err
+  }
+  pack(out[:], &p)
+  return out, nil
+}
+
+// HashToCurve25519 hashes msg to a curve25519 point with the suite
+// curve25519_XMD:SHA-512_ELL2_RO_ and returns its u-coordinate.
+func HashToCurve25519(msg []uint8, dst []uint8) (PublicKey, error) {
+  var p [4]gf
+  if err := hashToEdwards(&p, msg, dst, true); err != nil {
+    return PublicKey{}, err
+  }
+  return edwardsToMontgomery(&p), nil
+}
+
+// EncodeToCurve25519 is like HashToCurve25519 with the suite
+// curve25519_XMD:SHA-512_ELL2_NU_.
+func EncodeToCurve25519(msg []uint8, dst []uint8) (PublicKey, error) {
+  var p [4]gf
+  if err := hashToEdwards(&p, msg, dst, false); err != nil {
+    return PublicKey{}, err
+  }
+  return edwardsToMontgomery(&p), nil
+}
@@ -0,0 +1,74 @@
+// Key derivation from X25519 shared keys.
+//
+// DeriveKey runs HKDF (RFC 5869) over the checked X25519 output. Both public
+// keys are bound into the salt, sorted so that the two parties compute the
+// same key without agreeing on roles:
+//
+//   salt = min(pkA, pkB) || max(pkA, pkB)
+//   key  = HKDF-Expand(HKDF-Extract(salt, X25519(sk, pk)), info, length)
+//
+// X25519 ignores the top bit of a public key, so it is cleared in the salt
+// too: otherwise a peer key with the bit set would give the two parties
+// different salts for the same shared key.
+
+package axlsign
+
+import "bytes"
+import "crypto"
+import "crypto/sha256"
+import "crypto/sha512"
+import "errors"
+import "hash"
+import "curve25519-go/internal/hkdf"
+
+var errUnsupportedHash = errors.New("axlsign: unsupported hash")
+var errKeyLength = errors.New("axlsign: invalid derived key length")
+
+// DeriveKeyOptions are the options of DeriveKeyWithOptions.
+type DeriveKeyOptions struct {
+  // Hash is crypto.SHA256 or crypto.SHA512. Zero means crypto.SHA256.
+  Hash crypto.Hash
+}
+
+// DeriveKey returns length bytes derived with HKDF-SHA256 from the X25519
+// shared key between secretKey and peerPublicKey, both public keys and info.
+func DeriveKey(secretKey []uint8, peerPublicKey []uint8, info []uint8, length int) ([]uint8, error) {
+  return DeriveKeyWithOptions(secretKey, peerPublicKey, info, length, DeriveKeyOptions{})
+}
+
+// DeriveKeyWithOptions is like DeriveKey with the hash selected by opts.
+func DeriveKeyWithOptions(secretKey []uint8, peerPublicKey []uint8, info []uint8, length int, opts DeriveKeyOptions) ([]uint8, error) {
+  var h func() hash.Hash
+  switch (opts.Hash) {
+  case 0, crypto.SHA256:
+    h = sha256.New
+  case crypto.SHA512:
+    h = sha512.New
+  default:
+    return nil, errUnsupportedHash
+  }
+  if (length <= 0 || length > 255 * h().Size()) {
+    return nil, errKeyLength
+  }
+
+  var sharedKey, err = ECDH(secretKey, peerPublicKey)
+  if (err != nil) {
+    return nil, err
+  }
+
+  var publicKey = make([]uint8, 32)
+  crypto_scalarmult_base(publicKey, secretKey)
+
+  var peer = make([]uint8, 32)
+  copy(peer, peerPublicKey)
+  peer[31] = peer[31] & 127
+
+  var salt = make([]uint8, 0, 64)
+  if (bytes.Compare(publicKey, peer) < 0) {
+    salt = append(append(salt, publicKey...), peer...)
+  } else {
+    salt = append(append(salt, peer...), publicKey...)
+  }
+
+  return hkdf.Key(h, sharedKey, salt, info, length), nil
+}
@@ -0,0 +1,121 @@
+// Strongly typed keys and signatures.
+//
+// The fixed-size array types let the compiler reject a private key passed
+// where a public key is expected, and no length checks are needed at runtime.
+
+package axlsign
+
+import "crypto"
+import cryptorand "crypto/rand"
+import "crypto/subtle"
+import "io"
+
+// PublicKey is a 32-byte Curve25519 public key.
+type PublicKey [32]uint8
+
+// PrivateKey is a 32-byte Curve25519 private key.
+type PrivateKey [32]uint8
+
+// Signature is a 64-byte axlsign signature.
+type Signature [64]uint8
+
+// GenerateKey generates a new key pair from 32 bytes read from rand.
+func GenerateKey(rand io.Reader) (PublicKey, PrivateKey, error) {
+  var seed [32]uint8
+  if _, err := io.ReadFull(rand, seed[:]); err != nil {
+    return PublicKey{}, PrivateKey{}, err
+  }
+
+  var keys = GenerateKeyPair(seed[:])
+
+  var pub PublicKey
+  var priv PrivateKey
+  copy(pub[:], keys.PublicKey)
+  copy(priv[:], keys.PrivateKey)
+  return pub, priv, nil
+}
+
+// GenerateKeyDefault generates a new key pair using crypto/rand.
+func GenerateKeyDefault() (PublicKey, PrivateKey, error) {
+  return GenerateKey(cryptorand.Reader)
+}
+
+// SignRandom signs msg with priv, randomizing the signature with
+// 64 bytes read from crypto/rand.
+func SignRandom(priv PrivateKey, msg []uint8) (Signature, error) {
+  var sig Signature
+  var rnd = make([]uint8, 64)
+  if _, err := io.ReadFull(cryptorand.Reader, rnd); err != nil {
+    return sig, err
+  }
+  copy(sig[:], Sign(priv[:], msg, rnd))
+  return sig, nil
+}
+
+// Public returns the public key corresponding to priv.
+func (priv PrivateKey) Public() PublicKey {
+  var pub PublicKey
+  crypto_scalarmult_base(pub[:], priv[:])
+
+  // Remove sign bit from public key.
+  pub[31] = pub[31] & 127
+
+  return pub
+}
+
+// Sign signs msg with priv and returns the deterministic signature.
+func (priv PrivateKey) Sign(msg []uint8) Signature {
+  var sig Signature
+  curve25519_sign(sig[:], nil, msg, priv[:], nil)
+  return sig
+}
+
+// Verify reports whether sig is a valid signature of msg by pub.
+func (pub PublicKey) Verify(msg []uint8, sig Signature) bool {
+  return Verify(pub[:], msg, sig[:]) == 1
+}
+
+// ECDH returns the raw X25519 shared key between priv and the peer's pub.
+func (priv PrivateKey) ECDH(pub PublicKey) [32]uint8 {
+  var shared [32]uint8
+  crypto_scalarmult(shared[:], priv[:], pub[:])
+  return shared
+}
+
+// Equal reports whether pub and x have the same value.
+func (pub PublicKey) Equal(x crypto.PublicKey) bool {
+  var xx, ok = x.(PublicKey)
+  if (!ok) {
+    return false
+  }
+  return subtle.ConstantTimeCompare(pub[:], xx[:]) == 1
+}
+
+// SigningKey is a PrivateKey that implements crypto.Signer.
+type SigningKey PrivateKey
+
+var _ crypto.Signer = SigningKey{}
+
+// Public returns the PublicKey corresponding to k.
+func (k SigningKey) Public() crypto.PublicKey {
+  return PrivateKey(k).Public()
+}
+
+// Sign signs the unhashed message with k. opts.HashFunc() must return zero.
+// If rand is not nil, 64 bytes are read from it to randomize the signature,
+// otherwise the signature is deterministic.
+func (k SigningKey) Sign(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+  if (opts.HashFunc() != crypto.Hash(0)) {
+    return nil, ErrHashedMessage
+  }
+
+  var rnd []uint8
+  if (rand != nil) {
+    rnd = make([]uint8, 64)
+    if _, err := io.ReadFull(rand, rnd); err != nil {
+      return nil, err
+    }
+  }
+
+  return Sign(k[:], message, rnd), nil
+}
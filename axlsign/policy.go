@@ -0,0 +1,119 @@
+// Verification policies.
+//
+// Verify and VerifyEd25519 follow the original TweetNaCl code: the check is
+// cofactorless and, for Verify, S is not required to be below L, so one
+// message can have several valid signatures. VerifyOptions selects a
+// policy with well-defined malleability properties instead:
+//
+//   Policy      S < L  canonical A, R  small-order A, R  equation
+//   Strict      yes    yes             rejected          [S]B = R + [k]A
+//   Cofactored  yes    yes             rejected          [8][S]B = [8]R + [8][k]A
+//   ZIP215      yes    no              accepted          [8][S]B = [8]R + [8][k]A
+//
+// A canonical encoding has y < p and no sign bit when x = 0. ZIP215
+// follows https://zips.z.cash/zip-0215, under which validity never depends
+// on the implementation.
+
+package axlsign
+
+import "crypto/sha512"
+
+// VerifyPolicy selects the checks made by VerifyWithOptions.
+type VerifyPolicy int
+
+const (
+  // Strict: canonical encodings, no small-order points, cofactorless.
+  Strict VerifyPolicy = iota
+
+  // Cofactored: canonical encodings, no small-order points, cofactored.
+  Cofactored
+
+  // ZIP215: canonical S only, small-order points allowed, cofactored.
+  ZIP215
+)
+
+// VerifyOptions are the options of VerifyWithOptions and
+// VerifyEd25519WithOptions.
+type VerifyOptions struct {
+  Policy VerifyPolicy
+}
+
+// Returns true if b, which decodes to -p, is a canonical point encoding.
+func isCanonicalPoint(b []uint8, negP *[4]gf) bool {
+  var y [32]uint8
+  copy(y[:], b[:32])
+  y[31] = y[31] & 127
+
+  if (checkCanonical25519(y[:]) != 0) {
+    return false
+  }
+  return !(neq25519(&negP[0], &gf0) == 0 && b[31] & 128 != 0)
+}
+
+// Verifies the 64-byte signature sig of m by the Edwards public key pk
+// under the given policy.
+func verifyPolicy(sig []uint8, dom []uint8, m []uint8, pk []uint8, policy VerifyPolicy) bool {
+  var negA, negR, p, q [4]gf
+  var h [64]uint8
+
+  if (!isCanonicalScalar(sig[32:64])) {
+    return false
+  }
+
+  if ( unpackneg(&negA, pk) != 0 || unpackneg(&negR, sig[:32]) != 0 ) {
+    return false
+  }
+
+  if (policy != ZIP215) {
+    if (!isCanonicalPoint(pk, &negA) || !isCanonicalPoint(sig[:32], &negR)) {
+      return false
+    }
+    if (isSmallOrder(&negA) || isSmallOrder(&negR)) {
+      return false
+    }
+  }
+
+  var hs = sha512.New()
+  hs.Write(dom)
+  hs.Write(sig[:32])
+  hs.Write(pk[:32])
+  hs.Write(m)
+  hs.Sum(h[:0])
+  reduce(h[:])
+
+  // [S]B - R - [k]A
+  scalarmult(&p, &negA, h[:])
+  add(&p, &negR)
+  scalarbase(&q, sig[32:64])
+  add(&p, &q)
+
+  if (policy != Strict) {
+    add(&p, &p)
+    add(&p, &p)
+    add(&p, &p)
+  }
+
+  return isIdentity(&p)
+}
+
+// VerifyWithOptions reports whether sig is a valid signature of msg by pub
+// under opts.Policy. Strict and Cofactored also require pub to be below p.
+func VerifyWithOptions(pub PublicKey, msg []uint8, sig Signature, opts VerifyOptions) bool {
+  if (opts.Policy != ZIP215 && checkCanonical25519(pub[:]) != 0) {
+    return false
+  }
+
+  // Convert Curve25519 public key into Ed25519 public key and
+  // move the sign bit from the signature.
+  var edpk = convertPublicKey(pub[:])
+  edpk[31] = edpk[31] | ( sig[63] & 128)
+  sig[63] = sig[63] & 127
+
+  return verifyPolicy(sig[:], nil, msg, edpk[:], opts.Policy)
+}
+
+// VerifyEd25519WithOptions reports whether sig is a valid Ed25519 signature
+// of msg by pub under opts.Policy.
+func VerifyEd25519WithOptions(pub Ed25519PublicKey, msg []uint8, sig Signature, opts VerifyOptions) bool {
+  return verifyPolicy(sig[:], nil, msg, pub[:], opts.Policy)
+}
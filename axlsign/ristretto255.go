@@ -0,0 +1,293 @@
+// The ristretto255 prime-order group, as specified in RFC 9496:
+// https://www.rfc-editor.org/rfc/rfc9496
+//
+// Elements are represented by Edwards points; two points that differ by a
+// 4-torsion point are the same element, and encode to the same 32 bytes.
+
+package axlsign
+
+// Ristretto255 is an element of the ristretto255 group. The zero value is
+// not a valid element, use Ristretto255Identity.
+type Ristretto255 struct {
+  p [4]gf
+}
+
+// sqrt(a * d - 1)
+var sqrtADMinusOne = gf{0x2e1b, 0x497b, 0xf6a0, 0x7e97,
+                       0x54bd, 0x1b78, 0x8e0c, 0xaf9d,
+                       0xd1fd, 0x31f5, 0xfcc9, 0x0f3c,
+                       0x48ac, 0x2b83, 0x31bf, 0x3769}
+
+// 1 / sqrt(a - d)
+var invsqrtAMinusD = gf{0x40ea, 0x805d, 0xfdaa, 0x99c8,
+                       0x72be, 0x5a41, 0x1617, 0x9d2f,
+                       0xd840, 0xfe01, 0x7b91, 0x16c2,
+                       0xfca2, 0xcfaf, 0x8905, 0x786c}
+
+// 1 - d^2
+var oneMinusDSq = gf{0xc176, 0x945f, 0x09c1, 0xe27c,
+                    0x350f, 0xcd5e, 0xa138, 0x2c81,
+                    0xdfe4, 0xbe70, 0xabdd, 0x9994,
+                    0xe0d7, 0xb2b3, 0x72a8, 0x0290}
+
+// (d - 1)^2
+var dMinusOneSq = gf{0x4d20, 0x44ed, 0x5aaa, 0x31ad,
+                    0x1999, 0xb01e, 0x4a2c, 0xd29e,
+                    0x4eeb, 0x529b, 0xd32f, 0x4cdc,
+                    0x2241, 0xf66c, 0xb37a, 0x5968}
+
+// Returns 1 if a == b, 0 otherwise.
+func eq25519(a *gf, b *gf) int {
+  return 1 + neq25519(a, b)
+}
+
+// CT_ABS: sets o = |a|, the non-negative one of a and -a.
+func abs25519(o *gf, a *gf) {
+  var n gf
+  Z(&n, &gf0, a)
+  *o = *a
+  sel25519(o, &n, par25519(a))
+}
+
+// SQRT_RATIO_M1: sets r to the non-negative square root of u / v, or of
+// i * u / v if u / v is not a square. Returns 1 if u / v was a square.
+func sqrtRatioM1(r *gf, u *gf, v *gf) int {
+  var v3, v7, t, check, nu, nui, ri gf
+
+  // r = (u * v^3) * (u * v^7)^((p-5)/8)
+  S(&v3, v)
+  M(&v3, &v3, v)
+  S(&v7, &v3)
+  M(&v7, &v7, v)
+  M(&t, u, &v7)
+  pow2523(&t, &t)
+  M(&t, &t, u)
+  M(&t, &t, &v3)
+
+  // check = v * r^2
+  S(&check, &t)
+  M(&check, &check, v)
+
+  Z(&nu, &gf0, u)
+  M(&nui, &nu, &I)
+  var correct = eq25519(&check, u)
+  var flipped = eq25519(&check, &nu)
+  var flippedI = eq25519(&check, &nui)
+
+  M(&ri, &t, &I)
+  sel25519(&t, &ri, flipped | flippedI)
+  abs25519(r, &t)
+  return correct | flipped
+}
+
+// Ristretto255Identity returns the identity element.
+func Ristretto255Identity() Ristretto255 {
+  var e Ristretto255
+  set25519(&e.p[1], &gf1)
+  set25519(&e.p[2], &gf1)
+  return e
+}
+
+// Ristretto255Base returns the canonical generator, the Ed25519 base point.
+func Ristretto255Base() Ristretto255 {
+  var e Ristretto255
+  set25519(&e.p[0], &X)
+  set25519(&e.p[1], &Y)
+  set25519(&e.p[2], &gf1)
+  M(&e.p[3], &X, &Y)
+  return e
+}
+
+// DecodeRistretto255 decodes a 32-byte encoding. It returns
+// ErrInvalidEncoding unless b is the canonical encoding of an element.
+func DecodeRistretto255(b []uint8) (Ristretto255, error) {
+  var e Ristretto255
+  var s, ss, u1, u2, u2sq, v, t, invsqrt, denX, denY gf
+  var c [32]uint8
+
+  if (len(b) != 32) {
+    return e, ErrInvalidEncoding
+  }
+
+  // s must be a canonical, non-negative field element.
+  unpack25519(&s, b)
+  pack25519(c[:], &s)
+  if (crypto_verify_32(c[:], 0, b, 0) != 0 || b[0] & 1 != 0) {
+    return e, ErrInvalidEncoding
+  }
+
+  S(&ss, &s)
+  Z(&u1, &gf1, &ss)
+  A(&u2, &gf1, &ss)
+  S(&u2sq, &u2)
+
+  // v = -(d * u1^2) - u2^2
+  S(&v, &u1)
+  M(&v, &v, &D)
+  Z(&v, &gf0, &v)
+  Z(&v, &v, &u2sq)
+
+  M(&t, &v, &u2sq)
+  var wasSquare = sqrtRatioM1(&invsqrt, &gf1, &t)
+
+  M(&denX, &invsqrt, &u2)
+  M(&denY, &invsqrt, &denX)
+  M(&denY, &denY, &v)
+
+  // x = |2 * s * den_x|, y = u1 * den_y, t = x * y
+  A(&t, &s, &s)
+  M(&t, &t, &denX)
+  abs25519(&e.p[0], &t)
+  M(&e.p[1], &u1, &denY)
+  set25519(&e.p[2], &gf1)
+  M(&e.p[3], &e.p[0], &e.p[1])
+
+  if (wasSquare == 0 || par25519(&e.p[3]) != 0 || eq25519(&e.p[1], &gf0) != 0) {
+    return Ristretto255{}, ErrInvalidEncoding
+  }
+  return e, nil
+}
+
+// Encode returns the canonical 32-byte encoding of e.
+func (e Ristretto255) Encode() [32]uint8 {
+  var u1, u2, t, invsqrt, den1, den2, zInv, ix, iy, enchanted, x, y, ny, denInv gf
+  var out [32]uint8
+  var p = &e.p
+
+  // u1 = (z + y) * (z - y), u2 = x * y
+  A(&u1, &p[2], &p[1])
+  Z(&t, &p[2], &p[1])
+  M(&u1, &u1, &t)
+  M(&u2, &p[0], &p[1])
+
+  S(&t, &u2)
+  M(&t, &t, &u1)
+  sqrtRatioM1(&invsqrt, &gf1, &t)
+
+  M(&den1, &invsqrt, &u1)
+  M(&den2, &invsqrt, &u2)
+  M(&zInv, &den1, &den2)
+  M(&zInv, &zInv, &p[3])
+
+  M(&ix, &p[0], &I)
+  M(&iy, &p[1], &I)
+  M(&enchanted, &den1, &invsqrtAMinusD)
+
+  M(&t, &p[3], &zInv)
+  var rotate = par25519(&t)
+  x = p[0]
+  y = p[1]
+  denInv = den2
+  sel25519(&x, &iy, rotate)
+  sel25519(&y, &ix, rotate)
+  sel25519(&denInv, &enchanted, rotate)
+
+  M(&t, &x, &zInv)
+  Z(&ny, &gf0, &y)
+  sel25519(&y, &ny, par25519(&t))
+
+  // s = |den_inv * (z - y)|
+  Z(&t, &p[2], &y)
+  M(&t, &t, &denInv)
+  abs25519(&t, &t)
+  pack25519(out[:], &t)
+  return out
+}
+
+// MAP: sets p to the point for the field element t.
+func ristrettoMap(p *[4]gf, t *gf) {
+  var r, u, v, s, sp, c, n, w0, w1, w2, w3, tmp gf
+
+  // r = i * t^2
+  S(&r, t)
+  M(&r, &r, &I)
+
+  // u = (r + 1) * (1 - d^2)
+  A(&u, &r, &gf1)
+  M(&u, &u, &oneMinusDSq)
+
+  // v = (-1 - r * d) * (r + d)
+  M(&v, &r, &D)
+  Z(&v, &gf0, &v)
+  Z(&v, &v, &gf1)
+  A(&tmp, &r, &D)
+  M(&v, &v, &tmp)
+
+  var wasSquare = sqrtRatioM1(&s, &u, &v)
+  M(&sp, &s, t)
+  abs25519(&sp, &sp)
+  Z(&sp, &gf0, &sp)
+  sel25519(&s, &sp, 1 - wasSquare)
+  Z(&c, &gf0, &gf1)
+  tmp = r
+  sel25519(&c, &tmp, 1 - wasSquare)
+
+  // N = c * (r - 1) * (d - 1)^2 - v
+  Z(&n, &r, &gf1)
+  M(&n, &n, &c)
+  M(&n, &n, &dMinusOneSq)
+  Z(&n, &n, &v)
+
+  A(&w0, &s, &s)
+  M(&w0, &w0, &v)
+  M(&w1, &n, &sqrtADMinusOne)
+  S(&tmp, &s)
+  Z(&w2, &gf1, &tmp)
+  A(&w3, &gf1, &tmp)
+
+  M(&p[0], &w0, &w3)
+  M(&p[1], &w2, &w1)
+  M(&p[2], &w1, &w3)
+  M(&p[3], &w0, &w2)
+}
+
+// Ristretto255FromUniformBytes maps 64 uniformly random bytes, such as a
+// SHA-512 hash, to an element. The result is uniformly distributed and
+// nobody knows its discrete logarithm.
+func Ristretto255FromUniformBytes(b [64]uint8) Ristretto255 {
+  var e Ristretto255
+  var q [4]gf
+  var t gf
+
+  // unpack25519 drops the top bit of each half.
+  unpack25519(&t, b[:32])
+  ristrettoMap(&e.p, &t)
+  unpack25519(&t, b[32:])
+  ristrettoMap(&q, &t)
+  add(&e.p, &q)
+  return e
+}
+
+// Add returns e + q.
+func (e Ristretto255) Add(q Ristretto255) Ristretto255 {
+  add(&e.p, &q.p)
+  return e
+}
+
+// ScalarMult returns s * e, for a 32-byte little-endian scalar s.
+func (e Ristretto255) ScalarMult(s [32]uint8) Ristretto255 {
+  var r Ristretto255
+  scalarmult(&r.p, &e.p, s[:])
+  return r
+}
+
+// Ristretto255ScalarBaseMult returns s * B, for a 32-byte little-endian
+// scalar s and the generator B.
+func Ristretto255ScalarBaseMult(s [32]uint8) Ristretto255 {
+  var r Ristretto255
+  scalarbase(&r.p, s[:])
+  return r
+}
+
+// Equal reports whether e and q are the same element, in constant time.
+func (e Ristretto255) Equal(q Ristretto255) bool {
+  var a, b gf
+
+  // x1 * y2 == y1 * x2 or y1 * y2 == x1 * x2
+  M(&a, &e.p[0], &q.p[1])
+  M(&b, &e.p[1], &q.p[0])
+  var r = eq25519(&a, &b)
+  M(&a, &e.p[1], &q.p[1])
+  M(&b, &e.p[0], &q.p[0])
+  return r | eq25519(&a, &b) == 1
+}
@@ -0,0 +1,82 @@
+// Anonymous sealed boxes, compatible with libsodium crypto_box_seal.
+//
+// The sender encrypts with a fresh ephemeral key pair, so the recipient
+// learns nothing about who sent the message:
+//
+//   nonce = BLAKE2b-192(epk || pk)
+//   c     = epk || crypto_box(m, nonce, pk, esk)
+
+package axlsign
+
+import cryptorand "crypto/rand"
+import "io"
+
+// SealOverhead is the number of bytes SealAnonymous adds to a message.
+const SealOverhead = 32 + BoxOverhead
+
+// Sets nonce to BLAKE2b-192(epk || pk).
+func sealNonce(nonce []uint8, epk []uint8, pk []uint8) {
+  var in [64]uint8
+  copy(in[:32], epk)
+  copy(in[32:], pk)
+  blake2b(nonce[:24], in[:])
+}
+
+// SealAnonymous encrypts msg for the 32-byte public key recipientPub with a
+// fresh ephemeral key pair read from crypto/rand.
+func SealAnonymous(recipientPub []uint8, msg []uint8) ([]uint8, error) {
+  var esk [32]uint8
+  var epk [32]uint8
+  var nonce [24]uint8
+  var k [32]uint8
+  var zero [16]uint8
+
+  if (len(recipientPub) != 32) {
+    return nil, ErrInvalidKeySize
+  }
+  if _, err := io.ReadFull(cryptorand.Reader, esk[:]); err != nil {
+    return nil, err
+  }
+  crypto_scalarmult_base(epk[:], esk[:])
+
+  var s, err = ECDH(esk[:], recipientPub)
+  if (err != nil) {
+    return nil, err
+  }
+  crypto_core_hsalsa20(k[:], zero[:], s, sigma)
+
+  sealNonce(nonce[:], epk[:], recipientPub)
+
+  var out = make([]uint8, 32, SealOverhead + len(msg))
+  copy(out, epk[:])
+  return secretboxSeal(out, msg, nonce[:], k[:]), nil
+}
+
+// OpenAnonymous decrypts a ciphertext produced by SealAnonymous for
+// recipientKeys.PublicKey.
+func OpenAnonymous(recipientKeys Keys, ct []uint8) ([]uint8, error) {
+  var nonce [24]uint8
+  var k [32]uint8
+  var zero [16]uint8
+
+  if (len(recipientKeys.PublicKey) != 32 || len(recipientKeys.PrivateKey) != 32) {
+    return nil, ErrInvalidKeySize
+  }
+  if (len(ct) < SealOverhead) {
+    return nil, ErrInvalidCiphertext
+  }
+
+  var s, err = ECDH(recipientKeys.PrivateKey, ct[:32])
+  if (err != nil) {
+    return nil, ErrInvalidCiphertext
+  }
+  crypto_core_hsalsa20(k[:], zero[:], s, sigma)
+
+  sealNonce(nonce[:], ct[:32], recipientKeys.PublicKey)
+
+  var m, ok = secretboxOpen(nil, ct[32:], nonce[:], k[:])
+  if (!ok) {
+    return nil, ErrInvalidCiphertext
+  }
+  return m, nil
+}
@@ -0,0 +1,63 @@
+// Prehashed signatures over streams.
+//
+// The message is hashed with SHA-512 as it is written, and the 64-byte
+// digest is signed like Ed25519ph: every hash of the signature scheme is
+// prefixed with domPrehash, so a prehashed signature is never valid as a
+// plain axlsign signature and vice versa.
+
+package axlsign
+
+import "crypto/sha512"
+import "hash"
+
+var domPrehash = []uint8("axlsign prehash sha512 signature")
+
+// Signer signs the data written to it. It implements io.Writer.
+type Signer struct {
+  priv PrivateKey
+  h hash.Hash
+}
+
+// NewSigner returns a Signer that produces a prehashed signature by priv.
+func NewSigner(priv PrivateKey) *Signer {
+  return &Signer{priv, sha512.New()}
+}
+
+// Write adds more data to the message. It never returns an error.
+func (s *Signer) Write(p []uint8) (int, error) {
+  return s.h.Write(p)
+}
+
+// Sign returns the signature of the data written so far.
+func (s *Signer) Sign() Signature {
+  var ph [64]uint8
+  var sig Signature
+  s.h.Sum(ph[:0])
+  curve25519_sign(sig[:], domPrehash, ph[:], s.priv[:], nil)
+  return sig
+}
+
+// Verifier checks a prehashed signature of the data written to it.
+// It implements io.Writer.
+type Verifier struct {
+  pub PublicKey
+  sig Signature
+  h hash.Hash
+}
+
+// NewVerifier returns a Verifier that checks sig by pub.
+func NewVerifier(pub PublicKey, sig Signature) *Verifier {
+  return &Verifier{pub, sig, sha512.New()}
+}
+
+// Write adds more data to the message. It never returns an error.
+func (v *Verifier) Write(p []uint8) (int, error) {
+  return v.h.Write(p)
+}
+
+// Verify reports whether the signature is valid for the data written so far.
+func (v *Verifier) Verify() bool {
+  var ph [64]uint8
+  v.h.Sum(ph[:0])
+  return curve25519_sign_open(v.sig[:], domPrehash, ph[:], v.pub[:]) == 0
+}
@@ -0,0 +1,245 @@
+// VXEdDSA verifiable random function, as specified by Signal:
+// https://signal.org/docs/specifications/xeddsa/
+//
+// A proof is V || h || s. Anyone holding the public key can check it and
+// recover the same 32-byte output, which nobody can predict without the
+// private key.
+
+package axlsign
+
+import "io"
+
+// VRFProof is a 96-byte VXEdDSA proof.
+type VRFProof [96]uint8
+
+// VRFOutput is the 32-byte VXEdDSA output.
+type VRFOutput [32]uint8
+
+// Montgomery curve constant A = 486662.
+var _486662 = gf{0x6d06, 7}
+
+// Sets o = w^((p-1)/2): 1 if w is a non-zero square, -1 if it is not.
+func chi25519(o *gf, w *gf) {
+  var t gf
+
+  // (p-1)/2 = 4 * (p-5)/8 + 2
+  pow2523(&t, w)
+  S(&t, &t)
+  S(&t, &t)
+  M(&t, &t, w)
+  M(o, &t, w)
+}
+
+// Returns 1 if w is a square (including zero), 0 otherwise.
+func isSquare25519(w *gf) int {
+  var c, m gf
+  chi25519(&c, w)
+  Z(&m, &gf0, &gf1)
+  return -neq25519(&c, &m)
+}
+
+// Elligator 2 map with Z = 2: sets u to the Montgomery u-coordinate of
+// the curve point for the field element r.
+func elligator2(u *gf, r *gf) {
+  var t, u1, u2, w gf
+
+  // u1 = -A / (1 + 2 * r^2)
+  S(&t, r)
+  A(&t, &t, &t)
+  A(&t, &t, &gf1)
+  inv25519(&t, &t)
+  M(&u1, &_486662, &t)
+  Z(&u1, &gf0, &u1)
+
+  // w = u1 * (u1^2 + A * u1 + 1)
+  A(&t, &u1, &_486662)
+  M(&t, &t, &u1)
+  A(&t, &t, &gf1)
+  M(&w, &t, &u1)
+
+  // u = u1 if w is square, -A - u1 otherwise.
+  Z(&u2, &gf0, &_486662)
+  Z(&u2, &u2, &u1)
+  sel25519(&u1, &u2, 1 - isSquare25519(&w))
+  *u = u1
+}
+
+func negate(p *[4]gf) {
+  Z(&p[0], &gf0, &p[0])
+  Z(&p[3], &gf0, &p[3])
+}
+
+// Decodes the 32-byte encoding of a point into p, returns 0 on success.
+func unpack(p *[4]gf, b []uint8) int {
+  if ( unpackneg(p, b) != 0 ) {
+    return -1
+  }
+  negate(p)
+  return 0
+}
+
+// Returns 1 if 8 * p is the identity.
+func isSmallOrder(p *[4]gf) bool {
+  var q = *p
+  add(&q, &q)
+  add(&q, &q)
+  add(&q, &q)
+  return isIdentity(&q)
+}
+
+// hash_to_point(A || M): Elligator 2 on the first 255 bits of hash_2,
+// bit 255 as the sign, times the cofactor.
+func vxeddsaHashToPoint(p *[4]gf, a []uint8, msg []uint8) {
+  var h [64]uint8
+  var r, u, y, n, d gf
+  var enc [32]uint8
+
+  var hs = xeddsaHash(2)
+  hs.Write(a)
+  hs.Write(msg)
+  hs.Sum(h[:0])
+
+  var sign = h[31] & 128
+  h[31] = h[31] & 127
+  unpack25519(&r, h[:32])
+  elligator2(&u, &r)
+
+  // y = (u - 1) / (u + 1)
+  Z(&n, &u, &gf1)
+  A(&d, &u, &gf1)
+  inv25519(&d, &d)
+  M(&y, &n, &d)
+  pack25519(enc[:], &y)
+  enc[31] = enc[31] | sign
+
+  // The point is on the curve, so decoding cannot fail.
+  unpack(p, enc[:])
+  add(p, p)
+  add(p, p)
+  add(p, p)
+}
+
+// v = hash_5(8 * V) mod 2^256
+func vxeddsaOutput(v *VRFOutput, V *[4]gf) {
+  var q = *V
+  var enc [32]uint8
+  var h [64]uint8
+
+  add(&q, &q)
+  add(&q, &q)
+  add(&q, &q)
+  pack(enc[:], &q)
+
+  var hs = xeddsaHash(5)
+  hs.Write(enc[:])
+  hs.Sum(h[:0])
+  copy(v[:], h[:32])
+}
+
+// h = hash_4(A || V || R || Rv || M) mod q
+func vxeddsaChallenge(h []uint8, a []uint8, v []uint8, R *[4]gf, Rv *[4]gf, msg []uint8) {
+  var enc [32]uint8
+
+  var hs = xeddsaHash(4)
+  hs.Write(a)
+  hs.Write(v)
+  pack(enc[:], R)
+  hs.Write(enc[:])
+  pack(enc[:], Rv)
+  hs.Write(enc[:])
+  hs.Write(msg)
+  hs.Sum(h[:0])
+  reduce(h)
+}
+
+// VRFProve returns a VXEdDSA proof for msg by priv, and the VRF output,
+// reading the 64 bytes of random data Z from rand.
+func VRFProve(rand io.Reader, priv PrivateKey, msg []uint8) (VRFProof, VRFOutput, error) {
+  var proof VRFProof
+  var output VRFOutput
+  var edsk [64]uint8
+  var z [64]uint8
+  var r, h [64]uint8
+  var Bv, V, R, Rv, t [4]gf
+
+  if _, err := io.ReadFull(rand, z[:]); err != nil {
+    return proof, output, err
+  }
+
+  calculateKeyPair(edsk[:], priv[:])
+
+  vxeddsaHashToPoint(&Bv, edsk[32:], msg)
+  t = Bv
+  scalarmult(&V, &t, edsk[:32])
+  pack(proof[:32], &V)
+
+  // r = hash_3(a || V || Z) mod q
+  var hs = xeddsaHash(3)
+  hs.Write(edsk[:32])
+  hs.Write(proof[:32])
+  hs.Write(z[:])
+  hs.Sum(r[:0])
+  reduce(r[:])
+
+  scalarbase(&R, r[:])
+  t = Bv
+  scalarmult(&Rv, &t, r[:])
+
+  vxeddsaChallenge(h[:], edsk[32:], proof[:32], &R, &Rv, msg)
+  copy(proof[32:64], h[:32])
+
+  // s = r + h * a mod q
+  mulAddModL(proof[64:], h[:32], edsk[:32], r[:32])
+
+  vxeddsaOutput(&output, &V)
+  return proof, output, nil
+}
+
+// VRFVerify checks a VXEdDSA proof for msg by pub and returns the VRF
+// output, or ErrInvalidProof.
+func VRFVerify(pub PublicKey, msg []uint8, proof VRFProof) (VRFOutput, error) {
+  var output VRFOutput
+  var negA, A, Bv, V, R, Rv, t [4]gf
+  var hcheck [64]uint8
+
+  // u >= p, h >= 2^|q| or s >= q: a reduced s keeps proofs non-malleable.
+  if (checkCanonical25519(pub[:]) != 0 || proof[63] & 0xe0 != 0 || !isCanonicalScalar(proof[64:96])) {
+    return output, ErrInvalidProof
+  }
+
+  var edpk = convertPublicKey(pub[:])
+  if ( unpackneg(&negA, edpk[:]) != 0 || unpack(&V, proof[:32]) != 0 ) {
+    return output, ErrInvalidProof
+  }
+  A = negA
+  negate(&A)
+
+  vxeddsaHashToPoint(&Bv, edpk[:], msg)
+
+  if (isSmallOrder(&A) || isSmallOrder(&V) || isSmallOrder(&Bv)) {
+    return output, ErrInvalidProof
+  }
+
+  // R = s * B - h * A
+  t = negA
+  scalarmult(&R, &t, proof[32:64])
+  scalarbase(&t, proof[64:96])
+  add(&R, &t)
+
+  // Rv = s * Bv - h * V
+  t = V
+  negate(&t)
+  scalarmult(&Rv, &t, proof[32:64])
+  var sBv [4]gf
+  t = Bv
+  scalarmult(&sBv, &t, proof[64:96])
+  add(&Rv, &sBv)
+
+  vxeddsaChallenge(hcheck[:], edpk[:], proof[:32], &R, &Rv, msg)
+  if ( crypto_verify_32(hcheck[:], 0, proof[:], 32) != 0 ) {
+    return output, ErrInvalidProof
+  }
+
+  vxeddsaOutput(&output, &V)
+  return output, nil
+}
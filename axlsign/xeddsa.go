@@ -0,0 +1,123 @@
+// XEdDSA signatures, as specified by Signal:
+// https://signal.org/docs/specifications/xeddsa/
+//
+// Unlike the axlsign construction above, XEdDSA does not carry the sign bit
+// of the Edwards public key in the signature. calculate_key_pair negates the
+// private scalar when needed so that the Edwards public key always has a
+// zero sign bit, and the nonce is derived with hash_1.
+//
+// libsignal does not use this encoding either: it signs like XEdDSA but
+// stores the sign bit of A in the top bit of the signature, as axlsign
+// does, so its signatures are checked with Verify.
+
+package axlsign
+
+import "crypto/sha512"
+import "hash"
+import "io"
+
+// L - 1, the scalar -1 mod L.
+var lMinus1 = [32]uint8 { 0xec, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58,
+                          0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+                          0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10 }
+
+// Returns a SHA-512 state prefixed for hash_i: the 32-byte little-endian
+// encoding of 2^256 - 1 - i.
+func xeddsaHash(i int) hash.Hash {
+  var prefix [32]uint8
+  for j := 0; j < 32; j++ {
+    prefix[j] = 0xff
+  }
+  prefix[0] = uint8(0xff - i)
+
+  var h = sha512.New()
+  h.Write(prefix[:])
+  return h
+}
+
+// calculate_key_pair: sets edsk to a || A, where A = aB has a zero sign bit
+// and a = k or -k mod L for the clamped Montgomery private key k.
+func calculateKeyPair(edsk []uint8, k []uint8) {
+  var a [64]uint8
+  var neg [32]uint8
+  var zero [32]uint8
+  var p [4]gf
+
+  for i := 0; i < 32; i++ {
+    a[i] = k[i]
+  }
+  a[0] = a[0] & 248
+  a[31] = a[31] & 127
+  a[31] = a[31] | 64
+  reduce(a[:])
+
+  scalarbase(&p, a[:])
+  pack(edsk[32:], &p)
+
+  // If E.s == 1, use -a and -E; -E has the same y and a zero sign bit.
+  var s = edsk[63] >> 7
+  mulAddModL(neg[:], lMinus1[:], a[:32], zero[:])
+  var mask = -s
+  for i := 0; i < 32; i++ {
+    edsk[i] = a[i] ^ (mask & (a[i] ^ neg[i]))
+  }
+  edsk[63] = edsk[63] & 127
+}
+
+// SignXEdDSA returns the XEdDSA signature of msg by priv, reading the 64
+// bytes of random data Z from rand.
+func SignXEdDSA(rand io.Reader, priv PrivateKey, msg []uint8) (Signature, error) {
+  var sig Signature
+  var z [64]uint8
+
+  if _, err := io.ReadFull(rand, z[:]); err != nil {
+    return sig, err
+  }
+
+  xeddsaSign(sig[:], priv[:], msg, z[:])
+  return sig, nil
+}
+
+// xeddsa_sign with the 64 bytes of random data z.
+func xeddsaSign(sig []uint8, k []uint8, msg []uint8, z []uint8) {
+  var edsk [64]uint8
+  var r [64]uint8
+
+  calculateKeyPair(edsk[:], k)
+
+  // r = hash_1(a || M || Z)
+  var h = xeddsaHash(1)
+  h.Write(edsk[:32])
+  h.Write(msg)
+  h.Write(z)
+  h.Sum(r[:0])
+
+  crypto_sign_finish(sig, r[:], nil, msg, edsk[:])
+}
+
+// Returns 0 if the 32-byte u is below p.
+func checkCanonical25519(u []uint8) int {
+  var x gf
+  var t [32]uint8
+
+  if (u[31] & 128 != 0) {
+    return -1
+  }
+  unpack25519(&x, u)
+  pack25519(t[:], &x)
+  return crypto_verify_32(t[:], 0, u, 0)
+}
+
+// VerifyXEdDSA reports whether sig is a valid XEdDSA signature of msg by pub.
+func VerifyXEdDSA(pub PublicKey, msg []uint8, sig Signature) bool {
+  // u >= p or s >= 2^|q|
+  if (checkCanonical25519(pub[:]) != 0 || sig[63] & 0xe0 != 0) {
+    return false
+  }
+
+  // A = convert_mont(u), with a zero sign bit. Points off the curve
+  // fail to decode.
+  var edpk = convertPublicKey(pub[:])
+
+  return crypto_sign_open(sig[:], nil, msg, edpk[:]) == 0
+}
@@ -0,0 +1,374 @@
+// Package hpke implements Hybrid Public Key Encryption (RFC 9180) with
+// DHKEM(X25519, HKDF-SHA256), using axlsign keys for the KEM.
+//
+// All four modes are supported: Base, PSK, Auth and AuthPSK. The sender
+// key of the Auth modes is an axlsign.PrivateKey, so an identity key used
+// for signing can also authenticate HPKE messages.
+
+package hpke
+
+import "crypto/aes"
+import "crypto/cipher"
+import "crypto/sha256"
+import "crypto/sha512"
+import "encoding/binary"
+import "errors"
+import "hash"
+import "io"
+import "curve25519-go/axlsign"
+import "curve25519-go/internal/chacha20poly1305"
+import "curve25519-go/internal/hkdf"
+
+// KDF identifies a key derivation function.
+type KDF uint16
+
+const (
+  HKDFSHA256 KDF = 0x0001
+  HKDFSHA384 KDF = 0x0002
+  HKDFSHA512 KDF = 0x0003
+)
+
+// AEAD identifies an authenticated encryption algorithm.
+type AEAD uint16
+
+const (
+  AES128GCM AEAD = 0x0001
+  AES256GCM AEAD = 0x0002
+  ChaCha20Poly1305 AEAD = 0x0003
+
+  // ExportOnly contexts can only be used with Export.
+  ExportOnly AEAD = 0xffff
+)
+
+// Suite is the KDF and AEAD used by a context. The KEM is always
+// DHKEM(X25519, HKDF-SHA256).
+type Suite struct {
+  KDF KDF
+  AEAD AEAD
+}
+
+const kemID = 0x0020
+
+const (
+  modeBase uint8 = 0x00
+  modePSK uint8 = 0x01
+  modeAuth uint8 = 0x02
+  modeAuthPSK uint8 = 0x03
+)
+
+var (
+  // ErrInvalidSuite is returned for an unknown KDF or AEAD.
+  ErrInvalidSuite = errors.New("hpke: invalid suite")
+
+  // ErrInvalidPSK is returned when the PSK or PSK ID is missing, or
+  // given in a mode without PSK.
+  ErrInvalidPSK = errors.New("hpke: invalid PSK inputs")
+
+  // ErrInvalidEncapsulation is returned when enc is not a valid
+  // X25519 public key.
+  ErrInvalidEncapsulation = errors.New("hpke: invalid encapsulated key")
+
+  // ErrOpen is returned when a ciphertext fails authentication.
+  ErrOpen = errors.New("hpke: message authentication failed")
+
+  // ErrMessageLimit is returned when the sequence number is exhausted.
+  ErrMessageLimit = errors.New("hpke: message limit reached")
+
+  // ErrExportOnly is returned by Seal and Open in ExportOnly contexts.
+  ErrExportOnly = errors.New("hpke: export-only context")
+
+  // ErrExportLength is returned when an exported secret is too long.
+  ErrExportLength = errors.New("hpke: invalid export length")
+)
+
+var version = []uint8("HPKE-v1")
+
+// Returns the hash function of kdf, or nil.
+func (kdf KDF) hash() func() hash.Hash {
+  switch (kdf) {
+  case HKDFSHA256:
+    return sha256.New
+  case HKDFSHA384:
+    return sha512.New384
+  case HKDFSHA512:
+    return sha512.New
+  }
+  return nil
+}
+
+// Returns Nk and Nn, or ok = false for an unknown AEAD.
+func (aead AEAD) sizes() (nk int, nn int, ok bool) {
+  switch (aead) {
+  case AES128GCM:
+    return 16, 12, true
+  case AES256GCM:
+    return 32, 12, true
+  case ChaCha20Poly1305:
+    return 32, 12, true
+  case ExportOnly:
+    return 0, 0, true
+  }
+  return 0, 0, false
+}
+
+func (aead AEAD) new(key []uint8) (cipher.AEAD, error) {
+  switch (aead) {
+  case AES128GCM, AES256GCM:
+    var block, err = aes.NewCipher(key)
+    if (err != nil) {
+      return nil, err
+    }
+    return cipher.NewGCM(block)
+  case ChaCha20Poly1305:
+    return chacha20poly1305.New(key)
+  }
+  return nil, nil
+}
+
+// LabeledExtract(salt, label, ikm)
+func labeledExtract(h func() hash.Hash, suiteID []uint8, salt []uint8, label string, ikm []uint8) []uint8 {
+  var labeledIKM = append([]uint8{}, version...)
+  labeledIKM = append(labeledIKM, suiteID...)
+  labeledIKM = append(labeledIKM, label...)
+  labeledIKM = append(labeledIKM, ikm...)
+  return hkdf.Extract(h, salt, labeledIKM)
+}
+
+// LabeledExpand(prk, label, info, L)
+func labeledExpand(h func() hash.Hash, suiteID []uint8, prk []uint8, label string, info []uint8, length int) []uint8 {
+  var labeledInfo = binary.BigEndian.AppendUint16(nil, uint16(length))
+  labeledInfo = append(labeledInfo, version...)
+  labeledInfo = append(labeledInfo, suiteID...)
+  labeledInfo = append(labeledInfo, label...)
+  labeledInfo = append(labeledInfo, info...)
+  return hkdf.Expand(h, prk, labeledInfo, length)
+}
+
+var kemSuiteID = []uint8{ 'K', 'E', 'M', 0x00, kemID }
+
+// DeriveKeyPair returns the key pair derived from ikm, which should hold
+// at least 32 bytes of entropy.
+func DeriveKeyPair(ikm []uint8) (axlsign.PublicKey, axlsign.PrivateKey) {
+  var sk axlsign.PrivateKey
+  var prk = labeledExtract(sha256.New, kemSuiteID, nil, "dkp_prk", ikm)
+  copy(sk[:], labeledExpand(sha256.New, kemSuiteID, prk, "sk", nil, 32))
+  return sk.Public(), sk
+}
+
+// ExtractAndExpand(dh, kem_context)
+func extractAndExpand(dh []uint8, kemContext []uint8) []uint8 {
+  var prk = labeledExtract(sha256.New, kemSuiteID, nil, "eae_prk", dh)
+  return labeledExpand(sha256.New, kemSuiteID, prk, "shared_secret", kemContext, 32)
+}
+
+// Encap and AuthEncap, with skS nil for Encap. The ephemeral key pair is
+// derived from 32 bytes read from rand.
+func encap(rand io.Reader, pkR axlsign.PublicKey, skS *axlsign.PrivateKey) ([]uint8, []uint8, error) {
+  var ikm [32]uint8
+  if _, err := io.ReadFull(rand, ikm[:]); err != nil {
+    return nil, nil, err
+  }
+  var pkE, skE = DeriveKeyPair(ikm[:])
+
+  var dh, err = axlsign.ECDH(skE[:], pkR[:])
+  if (err != nil) {
+    return nil, nil, err
+  }
+  var kemContext = append(append([]uint8{}, pkE[:]...), pkR[:]...)
+
+  if (skS != nil) {
+    var dhS, err = axlsign.ECDH(skS[:], pkR[:])
+    if (err != nil) {
+      return nil, nil, err
+    }
+    var pkS = skS.Public()
+    dh = append(dh, dhS...)
+    kemContext = append(kemContext, pkS[:]...)
+  }
+
+  return extractAndExpand(dh, kemContext), pkE[:], nil
+}
+
+// Decap and AuthDecap, with pkS nil for Decap.
+func decap(enc []uint8, skR axlsign.PrivateKey, pkS *axlsign.PublicKey) ([]uint8, error) {
+  if (len(enc) != 32) {
+    return nil, ErrInvalidEncapsulation
+  }
+  var dh, err = axlsign.ECDH(skR[:], enc)
+  if (err != nil) {
+    return nil, ErrInvalidEncapsulation
+  }
+  var pkR = skR.Public()
+  var kemContext = append(append([]uint8{}, enc...), pkR[:]...)
+
+  if (pkS != nil) {
+    var dhS, err = axlsign.ECDH(skR[:], pkS[:])
+    if (err != nil) {
+      return nil, err
+    }
+    dh = append(dh, dhS...)
+    kemContext = append(kemContext, pkS[:]...)
+  }
+
+  return extractAndExpand(dh, kemContext), nil
+}
+
+// Context is an HPKE encryption context: the sender calls Seal and the
+// receiver Open, in the same order. Both can Export.
+type Context struct {
+  suite Suite
+  aead cipher.AEAD
+  baseNonce []uint8
+  seq uint64
+  exporterSecret []uint8
+}
+
+// KeySchedule(mode, shared_secret, info, psk, psk_id)
+func keySchedule(suite Suite, mode uint8, sharedSecret []uint8, info []uint8, psk []uint8, pskID []uint8) (*Context, error) {
+  var h = suite.KDF.hash()
+  var nk, nn, ok = suite.AEAD.sizes()
+  if (h == nil || !ok) {
+    return nil, ErrInvalidSuite
+  }
+
+  var hasPSK = len(psk) > 0
+  if (hasPSK != (len(pskID) > 0) || hasPSK != (mode == modePSK || mode == modeAuthPSK)) {
+    return nil, ErrInvalidPSK
+  }
+
+  var suiteID = []uint8{ 'H', 'P', 'K', 'E', 0x00, kemID }
+  suiteID = binary.BigEndian.AppendUint16(suiteID, uint16(suite.KDF))
+  suiteID = binary.BigEndian.AppendUint16(suiteID, uint16(suite.AEAD))
+
+  var pskIDHash = labeledExtract(h, suiteID, nil, "psk_id_hash", pskID)
+  var infoHash = labeledExtract(h, suiteID, nil, "info_hash", info)
+  var ksc = append(append([]uint8{ mode }, pskIDHash...), infoHash...)
+
+  var secret = labeledExtract(h, suiteID, sharedSecret, "secret", psk)
+
+  var c = &Context{ suite: suite }
+  c.exporterSecret = labeledExpand(h, suiteID, secret, "exp", ksc, h().Size())
+  if (suite.AEAD != ExportOnly) {
+    var key = labeledExpand(h, suiteID, secret, "key", ksc, nk)
+    c.baseNonce = labeledExpand(h, suiteID, secret, "base_nonce", ksc, nn)
+    var aead, err = suite.AEAD.new(key)
+    if (err != nil) {
+      return nil, err
+    }
+    c.aead = aead
+  }
+  return c, nil
+}
+
+// Returns base_nonce xor I2OSP(seq, Nn).
+func (c *Context) nonce() []uint8 {
+  var nonce = append([]uint8{}, c.baseNonce...)
+  var n = len(nonce)
+  for i := 0; i < 8; i++ {
+    nonce[n-1-i] ^= uint8(c.seq >> (8 * uint(i)))
+  }
+  return nonce
+}
+
+// Seal encrypts and authenticates pt with the additional data aad.
+func (c *Context) Seal(aad []uint8, pt []uint8) ([]uint8, error) {
+  if (c.aead == nil) {
+    return nil, ErrExportOnly
+  }
+  if (c.seq == ^uint64(0)) {
+    return nil, ErrMessageLimit
+  }
+  var ct = c.aead.Seal(nil, c.nonce(), pt, aad)
+  c.seq++
+  return ct, nil
+}
+
+// Open authenticates and decrypts ct with the additional data aad.
+func (c *Context) Open(aad []uint8, ct []uint8) ([]uint8, error) {
+  if (c.aead == nil) {
+    return nil, ErrExportOnly
+  }
+  if (c.seq == ^uint64(0)) {
+    return nil, ErrMessageLimit
+  }
+  var pt, err = c.aead.Open(nil, c.nonce(), ct, aad)
+  if (err != nil) {
+    return nil, ErrOpen
+  }
+  c.seq++
+  return pt, nil
+}
+
+// Export returns length bytes of secret derived from the context and
+// exporterContext.
+func (c *Context) Export(exporterContext []uint8, length int) ([]uint8, error) {
+  var h = c.suite.KDF.hash()
+  if (length < 0 || length > 255 * h().Size()) {
+    return nil, ErrExportLength
+  }
+  var suiteID = []uint8{ 'H', 'P', 'K', 'E', 0x00, kemID }
+  suiteID = binary.BigEndian.AppendUint16(suiteID, uint16(c.suite.KDF))
+  suiteID = binary.BigEndian.AppendUint16(suiteID, uint16(c.suite.AEAD))
+  return labeledExpand(h, suiteID, c.exporterSecret, "sec", exporterContext, length), nil
+}
+
+func setupS(suite Suite, mode uint8, rand io.Reader, pkR axlsign.PublicKey, info []uint8, psk []uint8, pskID []uint8, skS *axlsign.PrivateKey) ([]uint8, *Context, error) {
+  var sharedSecret, enc, err = encap(rand, pkR, skS)
+  if (err != nil) {
+    return nil, nil, err
+  }
+  var c, kerr = keySchedule(suite, mode, sharedSecret, info, psk, pskID)
+  if (kerr != nil) {
+    return nil, nil, kerr
+  }
+  return enc, c, nil
+}
+
+func setupR(suite Suite, mode uint8, enc []uint8, skR axlsign.PrivateKey, info []uint8, psk []uint8, pskID []uint8, pkS *axlsign.PublicKey) (*Context, error) {
+  var sharedSecret, err = decap(enc, skR, pkS)
+  if (err != nil) {
+    return nil, err
+  }
+  return keySchedule(suite, mode, sharedSecret, info, psk, pskID)
+}
+
+// SetupBaseS returns the encapsulated key and a sender context for pkR.
+// The ephemeral key is derived from 32 bytes read from rand.
+func SetupBaseS(suite Suite, rand io.Reader, pkR axlsign.PublicKey, info []uint8) ([]uint8, *Context, error) {
+  return setupS(suite, modeBase, rand, pkR, info, nil, nil, nil)
+}
+
+// SetupBaseR returns the receiver context for enc.
+func SetupBaseR(suite Suite, enc []uint8, skR axlsign.PrivateKey, info []uint8) (*Context, error) {
+  return setupR(suite, modeBase, enc, skR, info, nil, nil, nil)
+}
+
+// SetupPSKS is like SetupBaseS, also authenticated by a pre-shared key.
+func SetupPSKS(suite Suite, rand io.Reader, pkR axlsign.PublicKey, info []uint8, psk []uint8, pskID []uint8) ([]uint8, *Context, error) {
+  return setupS(suite, modePSK, rand, pkR, info, psk, pskID, nil)
+}
+
+// SetupPSKR is like SetupBaseR, also authenticated by a pre-shared key.
+func SetupPSKR(suite Suite, enc []uint8, skR axlsign.PrivateKey, info []uint8, psk []uint8, pskID []uint8) (*Context, error) {
+  return setupR(suite, modePSK, enc, skR, info, psk, pskID, nil)
+}
+
+// SetupAuthS is like SetupBaseS, also authenticated by the sender key skS.
+func SetupAuthS(suite Suite, rand io.Reader, pkR axlsign.PublicKey, info []uint8, skS axlsign.PrivateKey) ([]uint8, *Context, error) {
+  return setupS(suite, modeAuth, rand, pkR, info, nil, nil, &skS)
+}
+
+// SetupAuthR is like SetupBaseR, checking the sender key pkS.
+func SetupAuthR(suite Suite, enc []uint8, skR axlsign.PrivateKey, info []uint8, pkS axlsign.PublicKey) (*Context, error) {
+  return setupR(suite, modeAuth, enc, skR, info, nil, nil, &pkS)
+}
+
+// SetupAuthPSKS combines SetupAuthS and SetupPSKS.
+func SetupAuthPSKS(suite Suite, rand io.Reader, pkR axlsign.PublicKey, info []uint8, psk []uint8, pskID []uint8, skS axlsign.PrivateKey) ([]uint8, *Context, error) {
+  return setupS(suite, modeAuthPSK, rand, pkR, info, psk, pskID, &skS)
+}
+
+// SetupAuthPSKR combines SetupAuthR and SetupPSKR.
+func SetupAuthPSKR(suite Suite, enc []uint8, skR axlsign.PrivateKey, info []uint8, psk []uint8, pskID []uint8, pkS axlsign.PublicKey) (*Context, error) {
+  return setupR(suite, modeAuthPSK, enc, skR, info, psk, pskID, &pkS)
+}
@@ -0,0 +1,168 @@
+// Package chacha20poly1305 implements the ChaCha20-Poly1305 AEAD of
+// RFC 8439 for the protocol packages of this module.
+
+package chacha20poly1305
+
+import "crypto/cipher"
+import "crypto/subtle"
+import "encoding/binary"
+import "errors"
+import "curve25519-go/internal/poly1305"
+
+const (
+  // KeySize is the size of the key in bytes.
+  KeySize = 32
+
+  // NonceSize is the size of the nonce in bytes.
+  NonceSize = 12
+
+  // Overhead is the size of the Poly1305 tag in bytes.
+  Overhead = 16
+)
+
+var errOpen = errors.New("chacha20poly1305: message authentication failed")
+
+type chacha20poly1305 struct {
+  key [KeySize]uint8
+}
+
+// New returns a ChaCha20-Poly1305 AEAD with the given 32-byte key.
+func New(key []uint8) (cipher.AEAD, error) {
+  if (len(key) != KeySize) {
+    return nil, errors.New("chacha20poly1305: bad key length")
+  }
+  var c = new(chacha20poly1305)
+  copy(c.key[:], key)
+  return c, nil
+}
+
+func (c *chacha20poly1305) NonceSize() int {
+  return NonceSize
+}
+
+func (c *chacha20poly1305) Overhead() int {
+  return Overhead
+}
+
+func rotl(x uint32, n uint) uint32 {
+  return (x << n) | (x >> (32 - n))
+}
+
+func quarterRound(s *[16]uint32, a int, b int, c int, d int) {
+  s[a] += s[b]
+  s[d] = rotl(s[d] ^ s[a], 16)
+  s[c] += s[d]
+  s[b] = rotl(s[b] ^ s[c], 12)
+  s[a] += s[b]
+  s[d] = rotl(s[d] ^ s[a], 8)
+  s[c] += s[d]
+  s[b] = rotl(s[b] ^ s[c], 7)
+}
+
+// Sets out to the ChaCha20 block for key, counter and nonce.
+func block(out *[64]uint8, key *[KeySize]uint8, counter uint32, nonce []uint8) {
+  var s, x [16]uint32
+
+  s[0] = 0x61707865
+  s[1] = 0x3320646e
+  s[2] = 0x79622d32
+  s[3] = 0x6b206574
+  for i := 0; i < 8; i++ {
+    s[4+i] = binary.LittleEndian.Uint32(key[4*i:])
+  }
+  s[12] = counter
+  for i := 0; i < 3; i++ {
+    s[13+i] = binary.LittleEndian.Uint32(nonce[4*i:])
+  }
+
+  x = s
+  for i := 0; i < 10; i++ {
+    quarterRound(&x, 0, 4, 8, 12)
+    quarterRound(&x, 1, 5, 9, 13)
+    quarterRound(&x, 2, 6, 10, 14)
+    quarterRound(&x, 3, 7, 11, 15)
+    quarterRound(&x, 0, 5, 10, 15)
+    quarterRound(&x, 1, 6, 11, 12)
+    quarterRound(&x, 2, 7, 8, 13)
+    quarterRound(&x, 3, 4, 9, 14)
+  }
+  for i := 0; i < 16; i++ {
+    binary.LittleEndian.PutUint32(out[4*i:], x[i] + s[i])
+  }
+}
+
+// Sets dst to src xor the ChaCha20 stream starting at counter 1.
+func xorKeyStream(dst []uint8, src []uint8, key *[KeySize]uint8, nonce []uint8) {
+  var ks [64]uint8
+  var counter uint32 = 1
+  for i := 0; i < len(src); i += 64 {
+    block(&ks, key, counter, nonce)
+    counter++
+    for j := 0; j < 64 && i + j < len(src); j++ {
+      dst[i+j] = src[i+j] ^ ks[j]
+    }
+  }
+}
+
+// Sets out to the Poly1305 tag of aad and ct:
+// aad || pad16 || ct || pad16 || le64(len(aad)) || le64(len(ct))
+func tag(out []uint8, key *[KeySize]uint8, nonce []uint8, aad []uint8, ct []uint8) {
+  var polyKey [64]uint8
+  block(&polyKey, key, 0, nonce)
+
+  var pad = func(n int) int {
+    return (16 - n % 16) % 16
+  }
+  var m = make([]uint8, 0, len(aad) + len(ct) + 32 + 16)
+  m = append(m, aad...)
+  m = append(m, make([]uint8, pad(len(aad)))...)
+  m = append(m, ct...)
+  m = append(m, make([]uint8, pad(len(ct)))...)
+  m = binary.LittleEndian.AppendUint64(m, uint64(len(aad)))
+  m = binary.LittleEndian.AppendUint64(m, uint64(len(ct)))
+
+  poly1305.Sum(out, m, polyKey[:32])
+}
+
+// Returns the slice extended by n bytes, and the new bytes.
+func sliceForAppend(in []uint8, n int) ([]uint8, []uint8) {
+  var total = len(in) + n
+  var head []uint8
+  if (cap(in) >= total) {
+    head = in[:total]
+  } else {
+    head = make([]uint8, total)
+    copy(head, in)
+  }
+  return head, head[len(in):]
+}
+
+func (c *chacha20poly1305) Seal(dst []uint8, nonce []uint8, plaintext []uint8, additionalData []uint8) []uint8 {
+  if (len(nonce) != NonceSize) {
+    panic("chacha20poly1305: bad nonce length passed to Seal")
+  }
+  var ret, out = sliceForAppend(dst, len(plaintext) + Overhead)
+  xorKeyStream(out, plaintext, &c.key, nonce)
+  tag(out[len(plaintext):], &c.key, nonce, additionalData, out[:len(plaintext)])
+  return ret
+}
+
+func (c *chacha20poly1305) Open(dst []uint8, nonce []uint8, ciphertext []uint8, additionalData []uint8) ([]uint8, error) {
+  if (len(nonce) != NonceSize) {
+    panic("chacha20poly1305: bad nonce length passed to Open")
+  }
+  if (len(ciphertext) < Overhead) {
+    return nil, errOpen
+  }
+
+  var ct = ciphertext[:len(ciphertext) - Overhead]
+  var expected [Overhead]uint8
+  tag(expected[:], &c.key, nonce, additionalData, ct)
+  if (subtle.ConstantTimeCompare(expected[:], ciphertext[len(ct):]) != 1) {
+    return nil, errOpen
+  }
+
+  var ret, out = sliceForAppend(dst, len(ct))
+  xorKeyStream(out, ct, &c.key, nonce)
+  return ret, nil
+}
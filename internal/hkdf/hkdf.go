@@ -0,0 +1,43 @@
+// Package hkdf implements the HKDF key derivation function of RFC 5869
+// for the packages of this module.
+
+package hkdf
+
+import "crypto/hmac"
+import "hash"
+
+// Extract returns the pseudorandom key HKDF-Extract(salt, ikm).
+func Extract(h func() hash.Hash, salt []uint8, ikm []uint8) []uint8 {
+  if (salt == nil) {
+    salt = make([]uint8, h().Size())
+  }
+  var mac = hmac.New(h, salt)
+  mac.Write(ikm)
+  return mac.Sum(nil)
+}
+
+// Expand returns HKDF-Expand(prk, info, length). It panics if length is
+// more than 255 hash lengths.
+func Expand(h func() hash.Hash, prk []uint8, info []uint8, length int) []uint8 {
+  var mac = hmac.New(h, prk)
+  if (length > 255 * mac.Size()) {
+    panic("hkdf: length too large")
+  }
+
+  var okm = make([]uint8, 0, length)
+  var t []uint8
+  for i := 1; len(okm) < length; i++ {
+    mac.Reset()
+    mac.Write(t)
+    mac.Write(info)
+    mac.Write([]uint8{ uint8(i) })
+    t = mac.Sum(t[:0])
+    okm = append(okm, t...)
+  }
+  return okm[:length]
+}
+
+// Key returns HKDF-Expand(HKDF-Extract(salt, ikm), info, length).
+func Key(h func() hash.Hash, ikm []uint8, salt []uint8, info []uint8, length int) []uint8 {
+  return Expand(h, Extract(h, salt, ikm), info, length)
+}
@@ -0,0 +1,90 @@
+// Package poly1305 implements the Poly1305 one-time authenticator for
+// the packages of this module.
+//
+// Ported from TweetNaCl's crypto_onetimeauth.
+
+package poly1305
+
+func add1305(h *[17]uint32, c *[17]uint32) {
+  var u uint32 = 0
+  for j := 0; j < 17; j++ {
+    u += h[j] + c[j]
+    h[j] = u & 255
+    u >>= 8
+  }
+}
+
+var minusp = [17]uint32 { 5, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 252 }
+
+// Sum sets out[:16] to the Poly1305 tag of m under the 32-byte one-time
+// key k.
+func Sum(out []uint8, m []uint8, k []uint8) {
+  var x, r, h, c, g [17]uint32
+  var n = len(m)
+  var mi = 0
+
+  for j := 0; j < 16; j++ {
+    r[j] = uint32(k[j])
+  }
+  r[3] &= 15
+  r[4] &= 252
+  r[7] &= 15
+  r[8] &= 252
+  r[11] &= 15
+  r[12] &= 252
+  r[15] &= 15
+
+  for (n > 0) {
+    c = [17]uint32{}
+    var j = 0
+    for ; j < 16 && j < n; j++ {
+      c[j] = uint32(m[mi+j])
+    }
+    c[j] = 1
+    mi += j
+    n -= j
+    add1305(&h, &c)
+    for i := 0; i < 17; i++ {
+      x[i] = 0
+      for j := 0; j < 17; j++ {
+        if (j <= i) {
+          x[i] += h[j] * r[i - j]
+        } else {
+          x[i] += h[j] * (320 * r[i + 17 - j])
+        }
+      }
+    }
+    h = x
+    var u uint32 = 0
+    for j := 0; j < 16; j++ {
+      u += h[j]
+      h[j] = u & 255
+      u >>= 8
+    }
+    u += h[16]
+    h[16] = u & 3
+    u = 5 * (u >> 2)
+    for j := 0; j < 16; j++ {
+      u += h[j]
+      h[j] = u & 255
+      u >>= 8
+    }
+    u += h[16]
+    h[16] = u
+  }
+
+  g = h
+  add1305(&h, &minusp)
+  var s = -(h[16] >> 7)
+  for j := 0; j < 17; j++ {
+    h[j] ^= s & (g[j] ^ h[j])
+  }
+  for j := 0; j < 16; j++ {
+    c[j] = uint32(k[j + 16])
+  }
+  c[16] = 0
+  add1305(&h, &c)
+  for j := 0; j < 16; j++ {
+    out[j] = uint8(h[j])
+  }
+}
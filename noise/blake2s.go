@@ -0,0 +1,130 @@
+// BLAKE2s-256 (RFC 7693), as a hash.Hash for HMAC.
+
+package noise
+
+import "encoding/binary"
+import "hash"
+
+var blake2sIV = [8]uint32 {
+  0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a,
+  0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+var blake2sSigma = [10][16]uint8 {
+  { 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15 },
+  { 14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3 },
+  { 11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4 },
+  { 7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8 },
+  { 9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13 },
+  { 2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9 },
+  { 12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11 },
+  { 13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10 },
+  { 6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5 },
+  { 10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0 },
+}
+
+type blake2s struct {
+  h [8]uint32
+  t uint64
+  buf [64]uint8
+  n int
+}
+
+func newBLAKE2s() hash.Hash {
+  var d = new(blake2s)
+  d.Reset()
+  return d
+}
+
+func (d *blake2s) Size() int {
+  return 32
+}
+
+func (d *blake2s) BlockSize() int {
+  return 64
+}
+
+func (d *blake2s) Reset() {
+  d.h = blake2sIV
+  d.h[0] ^= 0x01010000 ^ 32
+  d.t = 0
+  d.n = 0
+}
+
+func rotr32(x uint32, n uint) uint32 {
+  return (x >> n) | (x << (32 - n))
+}
+
+func blake2sG(v *[16]uint32, a int, b int, c int, d int, x uint32, y uint32) {
+  v[a] = v[a] + v[b] + x
+  v[d] = rotr32(v[d] ^ v[a], 16)
+  v[c] = v[c] + v[d]
+  v[b] = rotr32(v[b] ^ v[c], 12)
+  v[a] = v[a] + v[b] + y
+  v[d] = rotr32(v[d] ^ v[a], 8)
+  v[c] = v[c] + v[d]
+  v[b] = rotr32(v[b] ^ v[c], 7)
+}
+
+func blake2sCompress(h *[8]uint32, block []uint8, t uint64, last bool) {
+  var v, m [16]uint32
+
+  for i := 0; i < 16; i++ {
+    m[i] = binary.LittleEndian.Uint32(block[4*i:])
+  }
+  for i := 0; i < 8; i++ {
+    v[i] = h[i]
+    v[i+8] = blake2sIV[i]
+  }
+  v[12] ^= uint32(t)
+  v[13] ^= uint32(t >> 32)
+  if (last) {
+    v[14] = ^v[14]
+  }
+
+  for i := 0; i < 10; i++ {
+    var s = &blake2sSigma[i]
+    blake2sG(&v, 0, 4, 8, 12, m[s[0]], m[s[1]])
+    blake2sG(&v, 1, 5, 9, 13, m[s[2]], m[s[3]])
+    blake2sG(&v, 2, 6, 10, 14, m[s[4]], m[s[5]])
+    blake2sG(&v, 3, 7, 11, 15, m[s[6]], m[s[7]])
+    blake2sG(&v, 0, 5, 10, 15, m[s[8]], m[s[9]])
+    blake2sG(&v, 1, 6, 11, 12, m[s[10]], m[s[11]])
+    blake2sG(&v, 2, 7, 8, 13, m[s[12]], m[s[13]])
+    blake2sG(&v, 3, 4, 9, 14, m[s[14]], m[s[15]])
+  }
+
+  for i := 0; i < 8; i++ {
+    h[i] ^= v[i] ^ v[i+8]
+  }
+}
+
+// Write keeps the last block buffered, as it must be compressed with the
+// final flag by Sum.
+func (d *blake2s) Write(p []uint8) (int, error) {
+  var n = len(p)
+  for (len(p) > 0) {
+    if (d.n == 64) {
+      d.t += 64
+      blake2sCompress(&d.h, d.buf[:], d.t, false)
+      d.n = 0
+    }
+    var c = copy(d.buf[d.n:], p)
+    d.n += c
+    p = p[c:]
+  }
+  return n, nil
+}
+
+func (d *blake2s) Sum(in []uint8) []uint8 {
+  var h = d.h
+  var block [64]uint8
+  copy(block[:], d.buf[:d.n])
+  blake2sCompress(&h, block[:], d.t + uint64(d.n), true)
+
+  var out [32]uint8
+  for i := 0; i < 8; i++ {
+    binary.LittleEndian.PutUint32(out[4*i:], h[i])
+  }
+  return append(in, out[:]...)
+}
@@ -0,0 +1,147 @@
+// Package noise implements the Noise Protocol Framework, revision 34:
+// https://noiseprotocol.org/noise.html
+//
+// The DH function is 25519, computed with axlsign's crypto_scalarmult, so
+// axlsign key pairs can be used as Noise static keys. The ciphers are
+// ChaChaPoly and AESGCM and the hashes SHA256, SHA512 and BLAKE2s. All the
+// one-way and interactive fundamental patterns are supported, with the
+// psk0 to psk3 modifiers.
+
+package noise
+
+import "crypto/aes"
+import "crypto/cipher"
+import "crypto/sha256"
+import "crypto/sha512"
+import "encoding/binary"
+import "errors"
+import "hash"
+import "io"
+import "strconv"
+import "curve25519-go/axlsign"
+import "curve25519-go/internal/chacha20poly1305"
+
+// MaxMsgLen is the maximum length of a Noise message.
+const MaxMsgLen = 65535
+
+const dhLen = 32
+
+var (
+  // ErrShortMessage is returned when a handshake message is truncated.
+  ErrShortMessage = errors.New("noise: message is too short")
+
+  // ErrMessageTooLong is returned when a message would exceed MaxMsgLen.
+  ErrMessageTooLong = errors.New("noise: message is too long")
+
+  // ErrDecrypt is returned when a message fails authentication.
+  ErrDecrypt = errors.New("noise: message authentication failed")
+
+  // ErrMaxNonce is returned when a cipher state has used all its nonces.
+  ErrMaxNonce = errors.New("noise: cipher state has run out of nonces")
+
+  // ErrUnexpectedCall is returned when WriteMessage or ReadMessage is
+  // called out of turn or after the handshake.
+  ErrUnexpectedCall = errors.New("noise: unexpected call")
+
+  // ErrMissingKey is returned when a key required by the pattern is
+  // missing from the Config.
+  ErrMissingKey = errors.New("noise: missing key")
+
+  // ErrInvalidPSK is returned when the pre-shared key is not 32 bytes.
+  ErrInvalidPSK = errors.New("noise: pre-shared key must be 32 bytes")
+)
+
+// Cipher selects the AEAD.
+type Cipher int
+
+const (
+  ChaChaPoly Cipher = iota
+  AESGCM
+)
+
+// Hash selects the hash function.
+type Hash int
+
+const (
+  SHA256 Hash = iota
+  SHA512
+  BLAKE2s
+)
+
+// CipherSuite is a cipher and a hash function; DH is always 25519.
+type CipherSuite struct {
+  Cipher Cipher
+  Hash Hash
+}
+
+func (c Cipher) String() string {
+  switch (c) {
+  case ChaChaPoly:
+    return "ChaChaPoly"
+  case AESGCM:
+    return "AESGCM"
+  }
+  return "Cipher(" + strconv.Itoa(int(c)) + ")"
+}
+
+func (h Hash) String() string {
+  switch (h) {
+  case SHA256:
+    return "SHA256"
+  case SHA512:
+    return "SHA512"
+  case BLAKE2s:
+    return "BLAKE2s"
+  }
+  return "Hash(" + strconv.Itoa(int(h)) + ")"
+}
+
+func (h Hash) new() func() hash.Hash {
+  switch (h) {
+  case SHA512:
+    return sha512.New
+  case BLAKE2s:
+    return newBLAKE2s
+  }
+  return sha256.New
+}
+
+// Returns the AEAD with key k and the nonce encoding of c.
+func (c Cipher) new(k [32]uint8) (cipher.AEAD, func([]uint8, uint64)) {
+  if (c == AESGCM) {
+    var block, _ = aes.NewCipher(k[:])
+    var aead, _ = cipher.NewGCM(block)
+    return aead, func(nonce []uint8, n uint64) {
+      binary.BigEndian.PutUint64(nonce[4:], n)
+    }
+  }
+  var aead, _ = chacha20poly1305.New(k[:])
+  return aead, func(nonce []uint8, n uint64) {
+    binary.LittleEndian.PutUint64(nonce[4:], n)
+  }
+}
+
+// DHKey is a 25519 key pair.
+type DHKey struct {
+  Private axlsign.PrivateKey
+  Public axlsign.PublicKey
+}
+
+// NewDHKey returns the key pair of an axlsign private key.
+func NewDHKey(priv axlsign.PrivateKey) DHKey {
+  return DHKey{ priv, priv.Public() }
+}
+
+// GenerateKeypair returns a new key pair from 32 bytes read from rand.
+func GenerateKeypair(rand io.Reader) (DHKey, error) {
+  var priv axlsign.PrivateKey
+  if _, err := io.ReadFull(rand, priv[:]); err != nil {
+    return DHKey{}, err
+  }
+  return NewDHKey(priv), nil
+}
+
+// DH(key_pair, public_key), with crypto_scalarmult.
+func dh(key DHKey, pub axlsign.PublicKey) []uint8 {
+  return axlsign.SharedKey(key.Private[:], pub[:])
+}
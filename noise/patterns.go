@@ -0,0 +1,161 @@
+// Handshake patterns, from section 7 of the Noise specification.
+
+package noise
+
+// Token is a handshake pattern token.
+type Token uint8
+
+const (
+  TokenE Token = iota
+  TokenS
+  TokenEE
+  TokenES
+  TokenSE
+  TokenSS
+  TokenPSK
+)
+
+// HandshakePattern is a Noise handshake pattern.
+type HandshakePattern struct {
+  Name string
+  InitiatorPreMessages []Token
+  ResponderPreMessages []Token
+  Messages [][]Token
+}
+
+// One-way patterns.
+var (
+  HandshakeN = HandshakePattern{
+    Name: "N",
+    ResponderPreMessages: []Token{ TokenS },
+    Messages: [][]Token{
+      { TokenE, TokenES },
+    },
+  }
+
+  HandshakeK = HandshakePattern{
+    Name: "K",
+    InitiatorPreMessages: []Token{ TokenS },
+    ResponderPreMessages: []Token{ TokenS },
+    Messages: [][]Token{
+      { TokenE, TokenES, TokenSS },
+    },
+  }
+
+  HandshakeX = HandshakePattern{
+    Name: "X",
+    ResponderPreMessages: []Token{ TokenS },
+    Messages: [][]Token{
+      { TokenE, TokenES, TokenS, TokenSS },
+    },
+  }
+)
+
+// Interactive patterns.
+var (
+  HandshakeNN = HandshakePattern{
+    Name: "NN",
+    Messages: [][]Token{
+      { TokenE },
+      { TokenE, TokenEE },
+    },
+  }
+
+  HandshakeNK = HandshakePattern{
+    Name: "NK",
+    ResponderPreMessages: []Token{ TokenS },
+    Messages: [][]Token{
+      { TokenE, TokenES },
+      { TokenE, TokenEE },
+    },
+  }
+
+  HandshakeNX = HandshakePattern{
+    Name: "NX",
+    Messages: [][]Token{
+      { TokenE },
+      { TokenE, TokenEE, TokenS, TokenES },
+    },
+  }
+
+  HandshakeXN = HandshakePattern{
+    Name: "XN",
+    Messages: [][]Token{
+      { TokenE },
+      { TokenE, TokenEE },
+      { TokenS, TokenSE },
+    },
+  }
+
+  HandshakeXK = HandshakePattern{
+    Name: "XK",
+    ResponderPreMessages: []Token{ TokenS },
+    Messages: [][]Token{
+      { TokenE, TokenES },
+      { TokenE, TokenEE },
+      { TokenS, TokenSE },
+    },
+  }
+
+  HandshakeXX = HandshakePattern{
+    Name: "XX",
+    Messages: [][]Token{
+      { TokenE },
+      { TokenE, TokenEE, TokenS, TokenES },
+      { TokenS, TokenSE },
+    },
+  }
+
+  HandshakeKN = HandshakePattern{
+    Name: "KN",
+    InitiatorPreMessages: []Token{ TokenS },
+    Messages: [][]Token{
+      { TokenE },
+      { TokenE, TokenEE, TokenSE },
+    },
+  }
+
+  HandshakeKK = HandshakePattern{
+    Name: "KK",
+    InitiatorPreMessages: []Token{ TokenS },
+    ResponderPreMessages: []Token{ TokenS },
+    Messages: [][]Token{
+      { TokenE, TokenES, TokenSS },
+      { TokenE, TokenEE, TokenSE },
+    },
+  }
+
+  HandshakeKX = HandshakePattern{
+    Name: "KX",
+    InitiatorPreMessages: []Token{ TokenS },
+    Messages: [][]Token{
+      { TokenE },
+      { TokenE, TokenEE, TokenSE, TokenS, TokenES },
+    },
+  }
+
+  HandshakeIN = HandshakePattern{
+    Name: "IN",
+    Messages: [][]Token{
+      { TokenE, TokenS },
+      { TokenE, TokenEE, TokenSE },
+    },
+  }
+
+  HandshakeIK = HandshakePattern{
+    Name: "IK",
+    ResponderPreMessages: []Token{ TokenS },
+    Messages: [][]Token{
+      { TokenE, TokenES, TokenS, TokenSS },
+      { TokenE, TokenEE, TokenSE },
+    },
+  }
+
+  HandshakeIX = HandshakePattern{
+    Name: "IX",
+    Messages: [][]Token{
+      { TokenE, TokenS },
+      { TokenE, TokenEE, TokenSE, TokenS, TokenES },
+    },
+  }
+)
@@ -0,0 +1,450 @@
+// CipherState, SymmetricState and HandshakeState, from section 5 of the
+// Noise specification.
+
+package noise
+
+import cryptorand "crypto/rand"
+import "crypto/cipher"
+import "hash"
+import "io"
+import "strconv"
+import "curve25519-go/axlsign"
+import "curve25519-go/internal/hkdf"
+
+// CipherState encrypts and decrypts transport messages with a key and a
+// counter nonce.
+type CipherState struct {
+  c Cipher
+  k [32]uint8
+  hasKey bool
+  n uint64
+  aead cipher.AEAD
+  putNonce func([]uint8, uint64)
+}
+
+func (cs *CipherState) initializeKey(c Cipher, k [32]uint8) {
+  cs.c = c
+  cs.k = k
+  cs.hasKey = true
+  cs.n = 0
+  cs.aead, cs.putNonce = c.new(k)
+}
+
+// Encrypt appends the encryption of pt with the additional data ad to out.
+// Before a key is set, pt is appended unencrypted.
+func (cs *CipherState) Encrypt(out []uint8, ad []uint8, pt []uint8) ([]uint8, error) {
+  if (!cs.hasKey) {
+    return append(out, pt...), nil
+  }
+  if (cs.n == ^uint64(0)) {
+    return nil, ErrMaxNonce
+  }
+  var nonce [12]uint8
+  cs.putNonce(nonce[:], cs.n)
+  cs.n++
+  return cs.aead.Seal(out, nonce[:], pt, ad), nil
+}
+
+// Decrypt appends the decryption of ct with the additional data ad to out.
+// The nonce is only advanced if ct is authentic.
+func (cs *CipherState) Decrypt(out []uint8, ad []uint8, ct []uint8) ([]uint8, error) {
+  if (!cs.hasKey) {
+    return append(out, ct...), nil
+  }
+  if (cs.n == ^uint64(0)) {
+    return nil, ErrMaxNonce
+  }
+  var nonce [12]uint8
+  cs.putNonce(nonce[:], cs.n)
+  var pt, err = cs.aead.Open(out, nonce[:], ct, ad)
+  if (err != nil) {
+    return nil, ErrDecrypt
+  }
+  cs.n++
+  return pt, nil
+}
+
+// Rekey replaces the key with the first 32 bytes of the encryption of 32
+// zero bytes under the maximum nonce.
+func (cs *CipherState) Rekey() {
+  var nonce [12]uint8
+  var zeros [32]uint8
+  var k [32]uint8
+  cs.putNonce(nonce[:], ^uint64(0))
+  copy(k[:], cs.aead.Seal(nil, nonce[:], zeros[:], nil))
+  var n = cs.n
+  cs.initializeKey(cs.c, k)
+  cs.n = n
+}
+
+// Nonce returns the next nonce.
+func (cs *CipherState) Nonce() uint64 {
+  return cs.n
+}
+
+type symmetricState struct {
+  cs CipherState
+  suite CipherSuite
+  h func() hash.Hash
+  ck []uint8
+  hh []uint8
+}
+
+// InitializeSymmetric(protocol_name)
+func (ss *symmetricState) initialize(suite CipherSuite, name []uint8) {
+  ss.suite = suite
+  ss.h = suite.Hash.new()
+  var hashLen = ss.h().Size()
+  if (len(name) <= hashLen) {
+    ss.hh = make([]uint8, hashLen)
+    copy(ss.hh, name)
+  } else {
+    var d = ss.h()
+    d.Write(name)
+    ss.hh = d.Sum(nil)
+  }
+  ss.ck = append([]uint8{}, ss.hh...)
+}
+
+// HKDF(ck, ikm, n) with the hash of the suite.
+func (ss *symmetricState) hkdf(ikm []uint8, n int) []uint8 {
+  return hkdf.Key(ss.h, ikm, ss.ck, nil, n * len(ss.ck))
+}
+
+func (ss *symmetricState) mixKey(ikm []uint8) {
+  var out = ss.hkdf(ikm, 2)
+  var hashLen = len(ss.ck)
+  var k [32]uint8
+  copy(ss.ck, out[:hashLen])
+  copy(k[:], out[hashLen:])
+  ss.cs.initializeKey(ss.suite.Cipher, k)
+}
+
+func (ss *symmetricState) mixHash(data []uint8) {
+  var d = ss.h()
+  d.Write(ss.hh)
+  d.Write(data)
+  ss.hh = d.Sum(ss.hh[:0])
+}
+
+func (ss *symmetricState) mixKeyAndHash(ikm []uint8) {
+  var out = ss.hkdf(ikm, 3)
+  var hashLen = len(ss.ck)
+  var k [32]uint8
+  copy(ss.ck, out[:hashLen])
+  ss.mixHash(out[hashLen:2*hashLen])
+  copy(k[:], out[2*hashLen:])
+  ss.cs.initializeKey(ss.suite.Cipher, k)
+}
+
+func (ss *symmetricState) encryptAndHash(out []uint8, pt []uint8) ([]uint8, error) {
+  var n = len(out)
+  var res, err = ss.cs.Encrypt(out, ss.hh, pt)
+  if (err != nil) {
+    return nil, err
+  }
+  ss.mixHash(res[n:])
+  return res, nil
+}
+
+func (ss *symmetricState) decryptAndHash(out []uint8, ct []uint8) ([]uint8, error) {
+  var res, err = ss.cs.Decrypt(out, ss.hh, ct)
+  if (err != nil) {
+    return nil, err
+  }
+  ss.mixHash(ct)
+  return res, nil
+}
+
+func (ss *symmetricState) split() (*CipherState, *CipherState) {
+  var out = ss.hkdf(nil, 2)
+  var hashLen = len(ss.ck)
+  var k1, k2 [32]uint8
+  copy(k1[:], out[:hashLen])
+  copy(k2[:], out[hashLen:])
+  var c1, c2 = new(CipherState), new(CipherState)
+  c1.initializeKey(ss.suite.Cipher, k1)
+  c2.initializeKey(ss.suite.Cipher, k2)
+  return c1, c2
+}
+
+// Config is the configuration of a HandshakeState.
+type Config struct {
+  CipherSuite CipherSuite
+  Pattern HandshakePattern
+  Initiator bool
+  Prologue []uint8
+
+  // StaticKeypair is the local static key, if the pattern has one.
+  StaticKeypair *DHKey
+
+  // EphemeralKeypair is only set for testing; it is generated otherwise.
+  EphemeralKeypair *DHKey
+
+  // PeerStatic and PeerEphemeral are the remote keys known in advance.
+  PeerStatic *axlsign.PublicKey
+  PeerEphemeral *axlsign.PublicKey
+
+  // PresharedKey is placed at PresharedKeyPlacement (the N of pskN).
+  PresharedKey []uint8
+  PresharedKeyPlacement int
+
+  // Random is the source of ephemeral keys; nil means crypto/rand.
+  Random io.Reader
+}
+
+// HandshakeState runs a handshake. WriteMessage and ReadMessage are called
+// in the order of the pattern; the last one returns the transport cipher
+// states.
+type HandshakeState struct {
+  ss symmetricState
+  s, e *DHKey
+  rs, re *axlsign.PublicKey
+  psk []uint8
+  initiator bool
+  messages [][]Token
+  msgIdx int
+  rand io.Reader
+}
+
+// Returns the protocol name and the messages with the psk token inserted.
+func protocol(c Config) (string, [][]Token) {
+  var messages = make([][]Token, len(c.Pattern.Messages))
+  for i := 0; i < len(messages); i++ {
+    messages[i] = append([]Token{}, c.Pattern.Messages[i]...)
+  }
+
+  var name = c.Pattern.Name
+  if (c.PresharedKey != nil) {
+    name += "psk" + strconv.Itoa(c.PresharedKeyPlacement)
+    if (c.PresharedKeyPlacement == 0) {
+      messages[0] = append([]Token{ TokenPSK }, messages[0]...)
+    } else {
+      var i = c.PresharedKeyPlacement - 1
+      messages[i] = append(messages[i], TokenPSK)
+    }
+  }
+  return "Noise_" + name + "_25519_" + c.CipherSuite.Cipher.String() + "_" + c.CipherSuite.Hash.String(), messages
+}
+
+// NewHandshakeState returns the HandshakeState for c.
+func NewHandshakeState(c Config) (*HandshakeState, error) {
+  if (c.PresharedKey != nil && (len(c.PresharedKey) != 32 || c.PresharedKeyPlacement < 0 || c.PresharedKeyPlacement > len(c.Pattern.Messages))) {
+    return nil, ErrInvalidPSK
+  }
+
+  var hs = &HandshakeState{
+    s: c.StaticKeypair,
+    e: c.EphemeralKeypair,
+    rs: c.PeerStatic,
+    re: c.PeerEphemeral,
+    psk: c.PresharedKey,
+    initiator: c.Initiator,
+    rand: c.Random,
+  }
+  if (hs.rand == nil) {
+    hs.rand = cryptorand.Reader
+  }
+
+  var name, messages = protocol(c)
+  hs.messages = messages
+  hs.ss.initialize(c.CipherSuite, []uint8(name))
+  hs.ss.mixHash(c.Prologue)
+
+  // Pre-messages: the initiator's keys, then the responder's.
+  var pre = func(tokens []Token, local bool) error {
+    for _, t := range tokens {
+      var pub *axlsign.PublicKey
+      switch {
+      case t == TokenS && local && hs.s != nil:
+        pub = &hs.s.Public
+      case t == TokenS && !local:
+        pub = hs.rs
+      case t == TokenE && local && hs.e != nil:
+        pub = &hs.e.Public
+      case t == TokenE && !local:
+        pub = hs.re
+      }
+      if (pub == nil) {
+        return ErrMissingKey
+      }
+      hs.ss.mixHash(pub[:])
+    }
+    return nil
+  }
+  if err := pre(c.Pattern.InitiatorPreMessages, c.Initiator); err != nil {
+    return nil, err
+  }
+  if err := pre(c.Pattern.ResponderPreMessages, !c.Initiator); err != nil {
+    return nil, err
+  }
+  return hs, nil
+}
+
+// Returns DH(local, remote) for the ee, es, se and ss tokens.
+func (hs *HandshakeState) dhToken(t Token) ([]uint8, error) {
+  var local *DHKey
+  var remote *axlsign.PublicKey
+  switch (t) {
+  case TokenEE:
+    local, remote = hs.e, hs.re
+  case TokenSS:
+    local, remote = hs.s, hs.rs
+  case TokenES:
+    if (hs.initiator) {
+      local, remote = hs.e, hs.rs
+    } else {
+      local, remote = hs.s, hs.re
+    }
+  case TokenSE:
+    if (hs.initiator) {
+      local, remote = hs.s, hs.re
+    } else {
+      local, remote = hs.e, hs.rs
+    }
+  }
+  if (local == nil || remote == nil) {
+    return nil, ErrMissingKey
+  }
+  return dh(*local, *remote), nil
+}
+
+func (hs *HandshakeState) myTurn() bool {
+  return (hs.msgIdx % 2 == 0) == hs.initiator
+}
+
+func (hs *HandshakeState) finish() (*CipherState, *CipherState) {
+  hs.msgIdx++
+  if (hs.msgIdx < len(hs.messages)) {
+    return nil, nil
+  }
+  return hs.ss.split()
+}
+
+// WriteMessage appends the next handshake message, carrying payload, to
+// out. After the last message it also returns the cipher states for
+// initiator to responder and responder to initiator.
+func (hs *HandshakeState) WriteMessage(out []uint8, payload []uint8) ([]uint8, *CipherState, *CipherState, error) {
+  if (hs.msgIdx >= len(hs.messages) || !hs.myTurn()) {
+    return nil, nil, nil, ErrUnexpectedCall
+  }
+  var start = len(out)
+  var err error
+
+  for _, t := range hs.messages[hs.msgIdx] {
+    switch (t) {
+    case TokenE:
+      if (hs.e == nil) {
+        var e, gerr = GenerateKeypair(hs.rand)
+        if (gerr != nil) {
+          return nil, nil, nil, gerr
+        }
+        hs.e = &e
+      }
+      out = append(out, hs.e.Public[:]...)
+      hs.ss.mixHash(hs.e.Public[:])
+      if (hs.psk != nil) {
+        hs.ss.mixKey(hs.e.Public[:])
+      }
+    case TokenS:
+      if (hs.s == nil) {
+        return nil, nil, nil, ErrMissingKey
+      }
+      if out, err = hs.ss.encryptAndHash(out, hs.s.Public[:]); err != nil {
+        return nil, nil, nil, err
+      }
+    case TokenPSK:
+      hs.ss.mixKeyAndHash(hs.psk)
+    default:
+      var k, derr = hs.dhToken(t)
+      if (derr != nil) {
+        return nil, nil, nil, derr
+      }
+      hs.ss.mixKey(k)
+    }
+  }
+
+  if out, err = hs.ss.encryptAndHash(out, payload); err != nil {
+    return nil, nil, nil, err
+  }
+  if (len(out) - start > MaxMsgLen) {
+    return nil, nil, nil, ErrMessageTooLong
+  }
+
+  var c1, c2 = hs.finish()
+  return out, c1, c2, nil
+}
+
+// ReadMessage processes the next handshake message and appends its payload
+// to out. After the last message it also returns the cipher states for
+// initiator to responder and responder to initiator.
+func (hs *HandshakeState) ReadMessage(out []uint8, message []uint8) ([]uint8, *CipherState, *CipherState, error) {
+  if (hs.msgIdx >= len(hs.messages) || hs.myTurn()) {
+    return nil, nil, nil, ErrUnexpectedCall
+  }
+  if (len(message) > MaxMsgLen) {
+    return nil, nil, nil, ErrMessageTooLong
+  }
+
+  for _, t := range hs.messages[hs.msgIdx] {
+    switch (t) {
+    case TokenE:
+      if (len(message) < dhLen) {
+        return nil, nil, nil, ErrShortMessage
+      }
+      var re axlsign.PublicKey
+      copy(re[:], message[:dhLen])
+      message = message[dhLen:]
+      hs.re = &re
+      hs.ss.mixHash(re[:])
+      if (hs.psk != nil) {
+        hs.ss.mixKey(re[:])
+      }
+    case TokenS:
+      var n = dhLen
+      if (hs.ss.cs.hasKey) {
+        n += 16
+      }
+      if (len(message) < n) {
+        return nil, nil, nil, ErrShortMessage
+      }
+      var rs, err = hs.ss.decryptAndHash(nil, message[:n])
+      if (err != nil) {
+        return nil, nil, nil, err
+      }
+      message = message[n:]
+      hs.rs = new(axlsign.PublicKey)
+      copy(hs.rs[:], rs)
+    case TokenPSK:
+      hs.ss.mixKeyAndHash(hs.psk)
+    default:
+      var k, err = hs.dhToken(t)
+      if (err != nil) {
+        return nil, nil, nil, err
+      }
+      hs.ss.mixKey(k)
+    }
+  }
+
+  var res, err = hs.ss.decryptAndHash(out, message)
+  if (err != nil) {
+    return nil, nil, nil, err
+  }
+
+  var c1, c2 = hs.finish()
+  return res, c1, c2, nil
+}
+
+// PeerStatic returns the remote static key, once known.
+func (hs *HandshakeState) PeerStatic() (axlsign.PublicKey, bool) {
+  if (hs.rs == nil) {
+    return axlsign.PublicKey{}, false
+  }
+  return *hs.rs, true
+}
+
+// ChannelBinding returns the handshake hash h, which identifies the
+// session once the handshake is complete.
+func (hs *HandshakeState) ChannelBinding() []uint8 {
+  return append([]uint8{}, hs.ss.hh...)
+}
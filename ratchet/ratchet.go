@@ -0,0 +1,467 @@
+// Package ratchet implements the Double Ratchet algorithm, as specified by
+// Signal: https://signal.org/docs/specifications/doubleratchet/
+//
+// The DH ratchet runs X25519 over axlsign keys and the KDF chains use
+// HKDF-SHA256 and HMAC-SHA256:
+//
+//   KDF_RK(rk, dh)  = HKDF(salt = rk, ikm = dh, info) -> rk, ck [, nhk]
+//   KDF_CK(ck)      = HMAC(ck, 0x02), HMAC(ck, 0x01)   -> ck, mk
+//   ENCRYPT(mk, m)  = ChaCha20-Poly1305 with the key and nonce HKDF(mk)
+//
+// With header encryption, headers are sealed with ChaCha20-Poly1305 under
+// the header keys and a random nonce.
+
+package ratchet
+
+import "bytes"
+import "crypto/hmac"
+import cryptorand "crypto/rand"
+import "crypto/sha256"
+import "crypto/subtle"
+import "encoding/binary"
+import "errors"
+import "io"
+import "curve25519-go/axlsign"
+import "curve25519-go/internal/chacha20poly1305"
+import "curve25519-go/internal/hkdf"
+
+const (
+  // DefaultMaxSkip is the default number of message keys that can be
+  // skipped in a single chain.
+  DefaultMaxSkip = 1000
+
+  // DefaultMaxSkippedKeys is the default number of skipped message keys
+  // kept for out-of-order messages.
+  DefaultMaxSkippedKeys = 2000
+
+  headerSize = 40
+)
+
+var (
+  // ErrTooManySkipped is returned when a message would skip more than
+  // MaxSkip message keys.
+  ErrTooManySkipped = errors.New("ratchet: too many skipped messages")
+
+  // ErrDecrypt is returned when a message or header fails authentication.
+  // The session is left unchanged.
+  ErrDecrypt = errors.New("ratchet: message authentication failed")
+
+  // ErrNoSendingChain is returned when the responder encrypts before it
+  // has received a message.
+  ErrNoSendingChain = errors.New("ratchet: no sending chain yet")
+
+  // ErrInvalidState is returned when a serialized session is malformed.
+  ErrInvalidState = errors.New("ratchet: invalid session state")
+)
+
+var defaultInfo = []uint8("curve25519-go ratchet")
+var messageInfo = []uint8("curve25519-go ratchet message keys")
+
+// Config holds the parameters of a session. Both parties must use the same
+// Info and header encryption setting.
+type Config struct {
+  // MaxSkip limits the message keys skipped in one chain; zero means
+  // DefaultMaxSkip.
+  MaxSkip int
+
+  // MaxSkippedKeys limits the stored skipped message keys, dropping the
+  // oldest; zero means DefaultMaxSkippedKeys.
+  MaxSkippedKeys int
+
+  // Info is the KDF_RK info; nil means "curve25519-go ratchet".
+  Info []uint8
+
+  // Rand is the source of new ratchet keys and header nonces; nil means
+  // crypto/rand.
+  Rand io.Reader
+}
+
+// Message is an encrypted message: the header, encrypted if the session
+// uses header encryption, and the ciphertext.
+type Message struct {
+  Header []uint8
+  Ciphertext []uint8
+}
+
+// A message key skipped in the chain identified by key (the ratchet public
+// key, or the header key with header encryption).
+type skippedKey struct {
+  key [32]uint8
+  n uint32
+  mk [32]uint8
+}
+
+// Session is the state of one party of a Double Ratchet session.
+type Session struct {
+  cfg Config
+
+  headerEncryption bool
+  hasDHr, hasCKs, hasCKr, hasHKs, hasHKr bool
+
+  dhs axlsign.PrivateKey
+  dhr axlsign.PublicKey
+  rk, cks, ckr [32]uint8
+  ns, nr, pn uint32
+  hks, hkr, nhks, nhkr [32]uint8
+
+  skipped []skippedKey
+}
+
+type header struct {
+  dh axlsign.PublicKey
+  pn uint32
+  n uint32
+}
+
+func (h *header) encode() []uint8 {
+  var b = make([]uint8, headerSize)
+  copy(b, h.dh[:])
+  binary.BigEndian.PutUint32(b[32:], h.pn)
+  binary.BigEndian.PutUint32(b[36:], h.n)
+  return b
+}
+
+func decodeHeader(b []uint8) (header, bool) {
+  var h header
+  if (len(b) != headerSize) {
+    return h, false
+  }
+  copy(h.dh[:], b)
+  h.pn = binary.BigEndian.Uint32(b[32:])
+  h.n = binary.BigEndian.Uint32(b[36:])
+  return h, true
+}
+
+func (s *Session) maxSkip() int {
+  if (s.cfg.MaxSkip > 0) {
+    return s.cfg.MaxSkip
+  }
+  return DefaultMaxSkip
+}
+
+func (s *Session) maxSkippedKeys() int {
+  if (s.cfg.MaxSkippedKeys > 0) {
+    return s.cfg.MaxSkippedKeys
+  }
+  return DefaultMaxSkippedKeys
+}
+
+func (s *Session) rand() io.Reader {
+  if (s.cfg.Rand != nil) {
+    return s.cfg.Rand
+  }
+  return cryptorand.Reader
+}
+
+// KDF_RK(rk, dh_out), and the next header key with header encryption.
+func (s *Session) kdfRK(dh []uint8) ([32]uint8, [32]uint8) {
+  var ck, nhk [32]uint8
+  var info = s.cfg.Info
+  if (info == nil) {
+    info = defaultInfo
+  }
+  var out = hkdf.Key(sha256.New, dh, s.rk[:], info, 96)
+  copy(s.rk[:], out[:32])
+  copy(ck[:], out[32:64])
+  copy(nhk[:], out[64:])
+  return ck, nhk
+}
+
+// KDF_CK(ck): advances ck and returns the message key.
+func kdfCK(ck *[32]uint8) [32]uint8 {
+  var mk [32]uint8
+  var mac = hmac.New(sha256.New, ck[:])
+  mac.Write([]uint8{ 0x01 })
+  mac.Sum(mk[:0])
+  mac.Reset()
+  mac.Write([]uint8{ 0x02 })
+  mac.Sum(ck[:0])
+  return mk
+}
+
+func encrypt(mk [32]uint8, pt []uint8, ad []uint8) []uint8 {
+  var kn = hkdf.Key(sha256.New, mk[:], nil, messageInfo, 32 + chacha20poly1305.NonceSize)
+  var aead, _ = chacha20poly1305.New(kn[:32])
+  return aead.Seal(nil, kn[32:], pt, ad)
+}
+
+func decrypt(mk [32]uint8, ct []uint8, ad []uint8) ([]uint8, error) {
+  var kn = hkdf.Key(sha256.New, mk[:], nil, messageInfo, 32 + chacha20poly1305.NonceSize)
+  var aead, _ = chacha20poly1305.New(kn[:32])
+  var pt, err = aead.Open(nil, kn[32:], ct, ad)
+  if (err != nil) {
+    return nil, ErrDecrypt
+  }
+  return pt, nil
+}
+
+// HENCRYPT(hk, header): nonce || ChaCha20-Poly1305(hk, nonce, header).
+func (s *Session) hencrypt(hk [32]uint8, h []uint8) ([]uint8, error) {
+  var nonce = make([]uint8, chacha20poly1305.NonceSize)
+  if _, err := io.ReadFull(s.rand(), nonce); err != nil {
+    return nil, err
+  }
+  var aead, _ = chacha20poly1305.New(hk[:])
+  return aead.Seal(nonce, nonce, h, nil), nil
+}
+
+// HDECRYPT(hk, enc_header)
+func hdecrypt(hk [32]uint8, enc []uint8) (header, bool) {
+  if (len(enc) < chacha20poly1305.NonceSize) {
+    return header{}, false
+  }
+  var aead, _ = chacha20poly1305.New(hk[:])
+  var nonce = enc[:chacha20poly1305.NonceSize]
+  var h, err = aead.Open(nil, nonce, enc[chacha20poly1305.NonceSize:], nil)
+  if (err != nil) {
+    return header{}, false
+  }
+  return decodeHeader(h)
+}
+
+// CONCAT(ad, header)
+func concat(ad []uint8, h []uint8) []uint8 {
+  return append(append([]uint8{}, ad...), h...)
+}
+
+func newSession(cfg Config) *Session {
+  return &Session{ cfg: cfg }
+}
+
+// NewInitiator starts a session as the party that sends first, with the
+// shared key sk (e.g. from X3DH) and the responder's ratchet public key.
+func NewInitiator(sk [32]uint8, peerPub axlsign.PublicKey, cfg Config) (*Session, error) {
+  var s = newSession(cfg)
+  s.rk = sk
+  s.dhr = peerPub
+  s.hasDHr = true
+  if err := s.sendingRatchet(); err != nil {
+    return nil, err
+  }
+  return s, nil
+}
+
+// NewResponder starts a session as the party that receives first, with
+// the shared key sk and its ratchet private key.
+func NewResponder(sk [32]uint8, priv axlsign.PrivateKey, cfg Config) *Session {
+  var s = newSession(cfg)
+  s.rk = sk
+  s.dhs = priv
+  return s
+}
+
+// NewInitiatorHE is like NewInitiator with header encryption, given the
+// two shared header keys.
+func NewInitiatorHE(sk [32]uint8, peerPub axlsign.PublicKey, sharedHKA [32]uint8, sharedNHKB [32]uint8, cfg Config) (*Session, error) {
+  var s = newSession(cfg)
+  s.headerEncryption = true
+  s.rk = sk
+  s.dhr = peerPub
+  s.hasDHr = true
+  s.hks = sharedHKA
+  s.hasHKs = true
+  s.nhkr = sharedNHKB
+  if err := s.sendingRatchet(); err != nil {
+    return nil, err
+  }
+  return s, nil
+}
+
+// NewResponderHE is like NewResponder with header encryption, given the
+// two shared header keys.
+func NewResponderHE(sk [32]uint8, priv axlsign.PrivateKey, sharedHKA [32]uint8, sharedNHKB [32]uint8, cfg Config) *Session {
+  var s = newSession(cfg)
+  s.headerEncryption = true
+  s.rk = sk
+  s.dhs = priv
+  s.nhks = sharedNHKB
+  s.nhkr = sharedHKA
+  return s
+}
+
+// SetConfig replaces the configuration, e.g. after UnmarshalBinary.
+func (s *Session) SetConfig(cfg Config) {
+  s.cfg = cfg
+}
+
+// Generates a new ratchet key pair and derives the sending chain from it.
+func (s *Session) sendingRatchet() error {
+  var _, priv, err = axlsign.GenerateKey(s.rand())
+  if (err != nil) {
+    return err
+  }
+  s.dhs = priv
+
+  var dh, derr = axlsign.ECDH(s.dhs[:], s.dhr[:])
+  if (derr != nil) {
+    return derr
+  }
+  var ck, nhk = s.kdfRK(dh)
+  s.cks = ck
+  s.hasCKs = true
+  if (s.headerEncryption) {
+    s.nhks = nhk
+  }
+  return nil
+}
+
+// Encrypt encrypts pt with the associated data ad.
+func (s *Session) Encrypt(pt []uint8, ad []uint8) (Message, error) {
+  if (!s.hasCKs) {
+    return Message{}, ErrNoSendingChain
+  }
+
+  var mk = kdfCK(&s.cks)
+  var h = header{ s.dhs.Public(), s.pn, s.ns }
+  var hb = h.encode()
+  if (s.headerEncryption) {
+    var enc, err = s.hencrypt(s.hks, hb)
+    if (err != nil) {
+      return Message{}, err
+    }
+    hb = enc
+  }
+  s.ns++
+
+  return Message{ hb, encrypt(mk, pt, concat(ad, hb)) }, nil
+}
+
+// Decrypt decrypts msg with the associated data ad. On error the session
+// is left unchanged.
+func (s *Session) Decrypt(msg Message, ad []uint8) ([]uint8, error) {
+  var t = s.clone()
+  var pt, err = t.decrypt(msg, ad)
+  if (err != nil) {
+    return nil, err
+  }
+  *s = *t
+  return pt, nil
+}
+
+func (s *Session) clone() *Session {
+  var t = *s
+  t.skipped = append([]skippedKey{}, s.skipped...)
+  return &t
+}
+
+func (s *Session) decrypt(msg Message, ad []uint8) ([]uint8, error) {
+  var pt, found, err = s.trySkippedMessageKeys(msg, ad)
+  if (found) {
+    return pt, err
+  }
+
+  var h header
+  var dhRatchet bool
+  if (s.headerEncryption) {
+    var ok bool
+    if h, dhRatchet, ok = s.decryptHeader(msg.Header); !ok {
+      return nil, ErrDecrypt
+    }
+  } else {
+    var ok bool
+    if h, ok = decodeHeader(msg.Header); !ok {
+      return nil, ErrDecrypt
+    }
+    dhRatchet = !s.hasDHr || subtle.ConstantTimeCompare(h.dh[:], s.dhr[:]) != 1
+  }
+
+  if (dhRatchet) {
+    if err := s.skipMessageKeys(h.pn); err != nil {
+      return nil, err
+    }
+    if err := s.dhRatchet(h); err != nil {
+      return nil, err
+    }
+  }
+  if err := s.skipMessageKeys(h.n); err != nil {
+    return nil, err
+  }
+
+  var mk = kdfCK(&s.ckr)
+  s.nr++
+  return decrypt(mk, msg.Ciphertext, concat(ad, msg.Header))
+}
+
+// Returns the header of enc, decrypted with HKr or else NHKr, and whether
+// it was NHKr, which starts a DH ratchet step.
+func (s *Session) decryptHeader(enc []uint8) (header, bool, bool) {
+  if (s.hasHKr) {
+    if h, ok := hdecrypt(s.hkr, enc); ok {
+      return h, false, true
+    }
+  }
+  var h, ok = hdecrypt(s.nhkr, enc)
+  return h, true, ok
+}
+
+// Returns the chain key identifying the receiving chain in skipped keys.
+func (s *Session) chainKey() [32]uint8 {
+  if (s.headerEncryption) {
+    return s.hkr
+  }
+  return s.dhr
+}
+
+func (s *Session) trySkippedMessageKeys(msg Message, ad []uint8) ([]uint8, bool, error) {
+  for i := 0; i < len(s.skipped); i++ {
+    var sk = &s.skipped[i]
+    var match bool
+    if (s.headerEncryption) {
+      var h, ok = hdecrypt(sk.key, msg.Header)
+      match = ok && h.n == sk.n
+    } else {
+      var h, ok = decodeHeader(msg.Header)
+      match = ok && bytes.Equal(h.dh[:], sk.key[:]) && h.n == sk.n
+    }
+    if (match) {
+      var mk = sk.mk
+      s.skipped = append(s.skipped[:i], s.skipped[i+1:]...)
+      var pt, err = decrypt(mk, msg.Ciphertext, concat(ad, msg.Header))
+      return pt, true, err
+    }
+  }
+  return nil, false, nil
+}
+
+func (s *Session) skipMessageKeys(until uint32) error {
+  if (uint64(s.nr) + uint64(s.maxSkip()) < uint64(until)) {
+    return ErrTooManySkipped
+  }
+  if (s.hasCKr) {
+    for (s.nr < until) {
+      var mk = kdfCK(&s.ckr)
+      s.skipped = append(s.skipped, skippedKey{ s.chainKey(), s.nr, mk })
+      s.nr++
+    }
+    if (len(s.skipped) > s.maxSkippedKeys()) {
+      s.skipped = append([]skippedKey{}, s.skipped[len(s.skipped) - s.maxSkippedKeys():]...)
+    }
+  }
+  return nil
+}
+
+func (s *Session) dhRatchet(h header) error {
+  s.pn = s.ns
+  s.ns = 0
+  s.nr = 0
+  if (s.headerEncryption) {
+    s.hks = s.nhks
+    s.hasHKs = true
+    s.hkr = s.nhkr
+    s.hasHKr = true
+  }
+  s.dhr = h.dh
+  s.hasDHr = true
+
+  var dh, err = axlsign.ECDH(s.dhs[:], s.dhr[:])
+  if (err != nil) {
+    return err
+  }
+  var ck, nhk = s.kdfRK(dh)
+  s.ckr = ck
+  s.hasCKr = true
+  if (s.headerEncryption) {
+    s.nhkr = nhk
+  }
+  return s.sendingRatchet()
+}
@@ -0,0 +1,124 @@
+// Session serialization.
+//
+// A session is stored as a version byte, a flags byte, the fixed-size
+// fields and the skipped message keys:
+//
+//   version || flags || DHs || DHr || RK || CKs || CKr || Ns || Nr || PN ||
+//   HKs || HKr || NHKs || NHKr || count || count * (key || n || mk)
+//
+// Absent keys are stored as zeros. The Config is not stored.
+
+package ratchet
+
+import "encoding/binary"
+
+const stateVersion = 1
+
+const (
+  flagHeaderEncryption = 1 << iota
+  flagDHr
+  flagCKs
+  flagCKr
+  flagHKs
+  flagHKr
+)
+
+const stateSize = 2 + 32 * 5 + 4 * 3 + 32 * 4 + 4
+
+// MarshalBinary returns the serialized session. It contains secret keys.
+func (s *Session) MarshalBinary() ([]uint8, error) {
+  var flags uint8
+  var set = func(f uint8, b bool) {
+    if (b) {
+      flags |= f
+    }
+  }
+  set(flagHeaderEncryption, s.headerEncryption)
+  set(flagDHr, s.hasDHr)
+  set(flagCKs, s.hasCKs)
+  set(flagCKr, s.hasCKr)
+  set(flagHKs, s.hasHKs)
+  set(flagHKr, s.hasHKr)
+
+  var b = make([]uint8, 0, stateSize + len(s.skipped) * 68)
+  b = append(b, stateVersion, flags)
+  b = append(b, s.dhs[:]...)
+  b = append(b, s.dhr[:]...)
+  b = append(b, s.rk[:]...)
+  b = append(b, s.cks[:]...)
+  b = append(b, s.ckr[:]...)
+  b = binary.BigEndian.AppendUint32(b, s.ns)
+  b = binary.BigEndian.AppendUint32(b, s.nr)
+  b = binary.BigEndian.AppendUint32(b, s.pn)
+  b = append(b, s.hks[:]...)
+  b = append(b, s.hkr[:]...)
+  b = append(b, s.nhks[:]...)
+  b = append(b, s.nhkr[:]...)
+  b = binary.BigEndian.AppendUint32(b, uint32(len(s.skipped)))
+  for i := 0; i < len(s.skipped); i++ {
+    b = append(b, s.skipped[i].key[:]...)
+    b = binary.BigEndian.AppendUint32(b, s.skipped[i].n)
+    b = append(b, s.skipped[i].mk[:]...)
+  }
+  return b, nil
+}
+
+// UnmarshalBinary restores a session serialized by MarshalBinary. The
+// Config is left as is; set it with SetConfig if needed.
+func (s *Session) UnmarshalBinary(b []uint8) error {
+  if (len(b) < stateSize || b[0] != stateVersion) {
+    return ErrInvalidState
+  }
+  var flags = b[1]
+  var count = binary.BigEndian.Uint32(b[stateSize - 4:])
+  if (uint64(len(b)) != uint64(stateSize) + uint64(count) * 68) {
+    return ErrInvalidState
+  }
+
+  var t = Session{ cfg: s.cfg }
+  t.headerEncryption = flags & flagHeaderEncryption != 0
+  t.hasDHr = flags & flagDHr != 0
+  t.hasCKs = flags & flagCKs != 0
+  t.hasCKr = flags & flagCKr != 0
+  t.hasHKs = flags & flagHKs != 0
+  t.hasHKr = flags & flagHKr != 0
+
+  var p = b[2:]
+  var next = func(n int) []uint8 {
+    var f = p[:n]
+    p = p[n:]
+    return f
+  }
+  copy(t.dhs[:], next(32))
+  copy(t.dhr[:], next(32))
+  copy(t.rk[:], next(32))
+  copy(t.cks[:], next(32))
+  copy(t.ckr[:], next(32))
+  t.ns = binary.BigEndian.Uint32(next(4))
+  t.nr = binary.BigEndian.Uint32(next(4))
+  t.pn = binary.BigEndian.Uint32(next(4))
+  copy(t.hks[:], next(32))
+  copy(t.hkr[:], next(32))
+  copy(t.nhks[:], next(32))
+  copy(t.nhkr[:], next(32))
+  next(4)
+
+  t.skipped = make([]skippedKey, count)
+  for i := 0; i < int(count); i++ {
+    copy(t.skipped[i].key[:], next(32))
+    t.skipped[i].n = binary.BigEndian.Uint32(next(4))
+    copy(t.skipped[i].mk[:], next(32))
+  }
+
+  *s = t
+  return nil
+}
+
+// Restore returns the session serialized by MarshalBinary, with cfg.
+func Restore(b []uint8, cfg Config) (*Session, error) {
+  var s = newSession(cfg)
+  if err := s.UnmarshalBinary(b); err != nil {
+    return nil, err
+  }
+  return s, nil
+}
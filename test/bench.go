@@ -0,0 +1,30 @@
+package main
+
+import "fmt"
+import "testing"
+import "curve25519-go/axlsign"
+
+// Benchmarks of the typed API, run with: go run ./test bench
+// Public, Sign, Verify and ECDH must not allocate.
+func benchmarks() {
+	var _, priv, _ = axlsign.GenerateKeyDefault()
+	var pub = priv.Public()
+	var msg = []uint8( "¡ lo esencial es invisible a los ojos !..." )
+	var sig = priv.Sign(msg)
+
+	var bench = func(name string, f func()) {
+		var allocs = testing.AllocsPerRun(10, f)
+		var res = testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				f()
+			}
+		})
+		fmt.Printf("%-8s %s %s (allocs/run: %v)\n", name, res.String(), res.MemString(), allocs)
+	}
+
+	bench("Public", func() { pub = priv.Public() })
+	bench("Sign", func() { sig = priv.Sign(msg) })
+	bench("Verify", func() { pub.Verify(msg, sig) })
+	bench("ECDH", func() { priv.ECDH(pub) })
+}
@@ -0,0 +1,122 @@
+package main
+
+import "crypto/sha512"
+import "encoding/hex"
+import "curve25519-go/axlsign"
+
+// RFC 8032, section 7: seed, public key, message, context, signature.
+var ed25519Vectors = []struct {
+	mode, seed, pub, msg, ctx, sig string
+}{
+	// 7.1 TEST 1
+	{"ed25519", "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+		"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a", "", "",
+		"e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e065224901555fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b"},
+	// 7.1 TEST 2
+	{"ed25519", "4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb",
+		"3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c", "72", "",
+		"92a009a9f0d4cab8720e820b5f642540a2b27b5416503f8fb3762223ebdb69da085ac1e43e15996e458f3613d0f11d8c387b2eaeb4302aeeb00d291612bb0c00"},
+	// 7.1 TEST 3
+	{"ed25519", "c5aa8df43f9f837bedb7442f31dcb7b166d38535076f094b85ce3a2e0b4458f7",
+		"fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025", "af82", "",
+		"6291d657deec24024827e69c3abe01a30ce548a284743a445e3680d7db5ac3ac18ff9b538d16f290ae67f760984dc6594a7c15e9716ed28dc027beceea1ec40a"},
+	// 7.2 foo
+	{"ed25519ctx", "0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6",
+		"dfc9425e4f968f7f0c29f0259cf5f9aed6851c2bb4ad8bfb860cfee0ab248292", "f726936d19c800494e3fdaff20b276a8", "666f6f",
+		"55a4cc2f70a54e04288c5f4cd1e45a7bb520b36292911876cada7323198dd87a8b36950b95130022907a7fb7c4e9b2d5f6cca685a587b4b21f4b888e4e7edb0d"},
+	// 7.3 TEST abc
+	{"ed25519ph", "833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42",
+		"ec172b93ad5e563bf4932c70e1245034c35467ef2efd4d64ebf819683467e2bf", "616263", "",
+		"98a70222f0b8121aa9d30f813d683f809e462b469c7ff87639499bb94e6dae4131f85042463c2a355a2003d062adf5aaa10b8c61e636062aaad11c2a26083406"},
+}
+
+// Checks the RFC 8032 test vectors, signing and verifying each one.
+func ed25519Test() bool {
+	for _, v := range ed25519Vectors {
+		var seed [32]uint8
+		var b, _ = hex.DecodeString(v.seed)
+		copy(seed[:], b)
+		var msg, _ = hex.DecodeString(v.msg)
+		var ctx, _ = hex.DecodeString(v.ctx)
+
+		var priv = axlsign.NewEd25519KeyFromSeed(seed)
+		var pub = priv.Public()
+		if hex.EncodeToString(pub[:]) != v.pub {
+			return false
+		}
+
+		var sig axlsign.Signature
+		var ok bool
+		switch v.mode {
+		case "ed25519":
+			sig = axlsign.SignEd25519(priv, msg)
+			ok = axlsign.VerifyEd25519(pub, msg, sig)
+		case "ed25519ctx":
+			sig, _ = axlsign.SignEd25519ctx(priv, msg, ctx)
+			ok = axlsign.VerifyEd25519ctx(pub, msg, sig, ctx)
+		case "ed25519ph":
+			var digest = sha512.Sum512(msg)
+			sig, _ = axlsign.SignEd25519ph(priv, digest, ctx)
+			ok = axlsign.VerifyEd25519ph(pub, digest, sig, ctx)
+		}
+		if !ok || hex.EncodeToString(sig[:]) != v.sig {
+			return false
+		}
+
+		sig[0] ^= 1
+		if axlsign.VerifyEd25519(pub, msg, sig) {
+			return false
+		}
+	}
+	return true
+}
+
+// Signatures by the identity key (so [h]A vanishes) with R = [S mod L]B
+// and S around 2^252 and L; only S below L may verify.
+var ed25519ScalarVectors = []struct {
+	r, s string
+	ok   bool
+}{
+	// S = 0
+	{"0100000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000000", true},
+	// S = 2^252 - 1
+	{"ee16e4099cbf9b5d456ece254ded2b241d1f5de8476d79d733cde687ef1025c9",
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff0f", true},
+	// S = 2^252
+	{"b8421c03ad2c038eacd7982913c60229b5d4e7cfcc8b83ec35c79c74b7ad855f",
+		"0000000000000000000000000000000000000000000000000000000000000010", true},
+	// S = L - 1
+	{"58666666666666666666666666666666666666666666666666666666666666e6",
+		"ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010", true},
+	// S = L
+	{"0100000000000000000000000000000000000000000000000000000000000000",
+		"edd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010", false},
+	// S = L + 1
+	{"5866666666666666666666666666666666666666666666666666666666666666",
+		"eed3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010", false},
+}
+
+// Returns the identity public key and its signature with the given R and S.
+func scalarSignature(r, s string) (axlsign.Ed25519PublicKey, axlsign.Signature) {
+	var pub axlsign.Ed25519PublicKey
+	pub[0] = 1
+	var sig axlsign.Signature
+	var rb, _ = hex.DecodeString(r)
+	var sb, _ = hex.DecodeString(s)
+	copy(sig[:32], rb)
+	copy(sig[32:], sb)
+	return pub, sig
+}
+
+// Checks that S is accepted up to L - 1 and rejected from L on.
+func ed25519ScalarTest() bool {
+	var msg = []uint8("S")
+	for _, v := range ed25519ScalarVectors {
+		var pub, sig = scalarSignature(v.r, v.s)
+		if axlsign.VerifyEd25519(pub, msg, sig) != v.ok {
+			return false
+		}
+	}
+	return true
+}
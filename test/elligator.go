@@ -0,0 +1,174 @@
+package main
+
+import "bytes"
+import cryptorand "crypto/rand"
+import "curve25519-go/axlsign"
+
+// Monocypher's crypto_elligator_map vectors (tis-ci-vectors.h), as used by
+// curve25519-voi: representative, then public key. The top two bits of
+// the representatives are padding.
+var elligatorMapVectors = []struct {
+	rep, pub string
+}{
+	{"0000000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000000"},
+	{"0000000000000000000000000000000000000000000000000000000000000040",
+		"0000000000000000000000000000000000000000000000000000000000000000"},
+	{"0000000000000000000000000000000000000000000000000000000000000080",
+		"0000000000000000000000000000000000000000000000000000000000000000"},
+	{"00000000000000000000000000000000000000000000000000000000000000c0",
+		"0000000000000000000000000000000000000000000000000000000000000000"},
+	{"673a505e107189ee54ca93310ac42e4545e9e59050aaac6f8b5f64295c8ec02f",
+		"242ae39ef158ed60f20b89396d7d7eef5374aba15dc312a6aea6d1e57cacf85e"},
+	{"922688fa428d42bc1fa8806998fbc5959ae801817e85a42a45e8ec25a0d7545a",
+		"696f341266c64bcfa7afa834f8c34b2730be11c932e08474d1a22f26ed82410b"},
+	{"0d3b0eb88b74ed13d5f6a130e03c4ad607817057dc227152827c0506a538bbba",
+		"0b00df174d9fb0b6ee584d2cf05613130bad18875268c38b377e86dfefef177f"},
+	{"01a3ea5658f4e00622eeacf724e0bd82068992fae66ed2b04a8599be16662ef5",
+		"7ae4c58bc647b5646c9f5ae4c2554ccbf7c6e428e7b242a574a5a9c293c21f7e"},
+	{"69599ab5a829c3e9515128d368da7354a8b69fcee4e34d0a668b783b6cae550f",
+		"09024abaaef243e3b69366397e8dfc1fdc14a0ecc7cf497cbe4f328839acce69"},
+	{"9172922f96d2fa41ea0daf961857056f1656ab8406db80eaeae76af58f8c9f50",
+		"beab745a2a4b4e7f1a7335c3ffcdbd85139f3a72b667a01ee3e3ae0e530b3372"},
+	{"6850a20ac5b6d2fa7af7042ad5be234d3311b9fb303753dd2b610bd566983281",
+		"1287388eb2beeff706edb9cf4fcfdd35757f22541b61528570b86e8915be1530"},
+	{"84417826c0e80af7cb25a73af1ba87594ff7048a26248b5757e52f2824e068f1",
+		"51acd2e8910e7d28b4993db7e97e2b995005f26736f60dcdde94bdf8cb542251"},
+	{"b0fbe152849f49034d2fa00ccc7b960fad7b30b6c4f9f2713eb01c147146ad31",
+		"98508bb3590886af3be523b61c3d0ce6490bb8b27029878caec57e4c750f993d"},
+	{"a0ca9ff75afae65598630b3b93560834c7f4dd29a557aa29c7becd49aeef3753",
+		"3c5fad0516bb8ec53da1c16e910c23f792b971c7e2a0ee57d57c32e3655a646b"},
+}
+
+// obfs4's inverse map, from the extra25519 package it ships
+// (github.com/agl/ed25519/extra25519, ScalarBaseMult): private key, public
+// key, the representatives for tweak bit 0, r^2 = -u / (2 (u + A)), and
+// bit 1, r^2 = -(u + A) / (2 u), and the bit obfs4 picks. obfs4 does not
+// bound r, so each one is given as the root at most (p-1)/2. Keys whose
+// public key has no representative have none.
+var elligatorRevVectors = []struct {
+	priv, pub, rep0, rep1 string
+	bit                   uint8
+}{
+	{"90530ccfd841dfe7521375e3a01bad7316a1edb9b426fb6e8fdd952c60f00dde",
+		"d4af319174d82b9aa5cb3f2c22d8133cf22a13f97f2a60299c44840d5eec3f55",
+		"2a487d31d9e3b4c51d129381b869969b9016bfc4abbd46dd318fc5e80cf62132",
+		"f864cfe3366b7000bf02f78db9c86f79ba69959418437d04a8bb3583f68a8417", 0},
+	{"6284e6e57e20f0616d4ad7319aea08b6dac09c1dce355bde28247a5af0fb297f",
+		"1963d5f6e3cc7ce1aa10e541c728d9c95e0efbbab9d31ec76a659fac869e9117",
+		"", "", 0},
+	{"a4e7728168d9a02f439079e286bb290cb3ead576a48c6f6a7514b0cbf2242004",
+		"c46dc2dc6656cd47e1766084fe12ae474a71db1c02c44dd2f6f11f0b4b0f2538",
+		"", "", 0},
+	{"e9fe20491e9a48f3f48438fc66965d05e1dcc545a7358c20dbfd4d5ccadd111a",
+		"1456a8052eee92d020c13ae660c30dd4be6ed8c30f03cf81dc5e308261335602",
+		"", "", 0},
+	{"5f94870792dc17715c79f94b98d37a654602b725dc94b8ff631f1472102dc722",
+		"b77b8e09df0b275cf13e0a8579ef66cd3f46af2d6030e26af6d4f30afe67272e",
+		"64f663ed07a6c66132292d56f0332f7bd202f2d47ae287cef1252c4454ceca0a",
+		"92c1d5934eadb3a44f829ff1078b8d73085c56ee309a8ad171eb95f4ad06cc13", 1},
+	{"eee97811044a702c866743806818f66639dc9a18c3d68d6fdc47da58872378d4",
+		"61f61f3b4a53ab85b7eea54fc843733c5dcf2dc1207913fae86f75e6e2c3f75a",
+		"", "", 0},
+	{"1fe4e54ba8e6132827f7e14301a59e0daea6395371ff8d094a1be365fa96a779",
+		"bf4f4f52639862880db659ba07c30b011061cd8f51b4f870d7049395eaf02639",
+		"6d5528771ced2e716b2b0d3d2397c8f231d1d39361dacda1a45d9b9020c80839",
+		"faa8cab29e8ebfe283548c2611e25a138dd6e95026b04bde8bd0a65afc9c212f", 0},
+	{"104a4a60d810c268ef562c1ae15d944962eec77ada9dcb571268ed0a4c2d1ea9",
+		"bfdc80aeaae2cb4b31573daec0d5ff5ef526a16a7b66aae860b88dd810b51d5c",
+		"84ef46ea943ef6ef6d24f0c6e7048075a24f930cda552c18591c2a809606211a",
+		"ea05d9d03b3aad0fb4619400eb637b941e6f04f9f5dc7f260e0efa85e182671f", 1},
+	{"9d0d926c1bf7a31091e48efb34882a16fe62346c501788c25cfbd144eca3bf28",
+		"f79289438c9757570a9ed10d80c659ce22205fb2aa8137f5754f934f01fc9b66",
+		"7526bb9b77b52e399a30570ca978044fc5a5197b564ae7752605bcdf9e57d11c",
+		"c1b7d6e8ef4d0154a987e9d33fe3ad6fe7f8e365df70e8bf2c8031d8b446e53a", 1},
+	{"1222401c797f93ea287a80718dd6055f45f3a4cc9e05a48293a1b12c2587c0e9",
+		"07a9353283e4ee5923149637fb0d6170e4821fb20cf612623251116ddf369300",
+		"e83eebee016375a0376ea6ef2742b06651afff97f7a7ddaa15fe1790a83b132e",
+		"1a7b86f5a6a97c758d052d83f2b2cd736c3ecb2f9fe7d6fd551707f65165fa1a", 1},
+	{"7f2a87399023b6427096b59c3266953718ecf80a8b45e0aeff80d1a995a3b9c8",
+		"e0ac02e6557d85715f367683b070316ba4913c44b2d5b8875c694f5875fe703d",
+		"48746fda12acb365b23dc78a2f7839b2e72de7cc65cc57a2dc4dba81b686120e",
+		"9c7567000c2367a0e388e2f9fb56ba4ae6cc8d677cfabfa7012d3056f402fe1b", 0},
+	{"698ba01d9dcf6dc2e1e46ce7503ecddf8943bc33e77d4cbbdc4e114b5130da3d",
+		"d62f41a2107342dc8ab472ecc25525f052687b8ac6900473b7119cc795f5b275",
+		"f9fa7f40667f973067b8d128f7e1c420d0394db74b93e15573e6126658b63728",
+		"f6c0e120e6f72d82a0a682bac528a22636113bbb80d0573d25fb908faee94f29", 0},
+}
+
+// Checks the map and inverse map vectors.
+func elligatorVectorsTest() bool {
+	for _, v := range elligatorMapVectors {
+		var rep axlsign.Representative
+		copy(rep[:], unhex(v.rep))
+		var pub = axlsign.RepresentativeToPublicKey(rep)
+		if !bytes.Equal(pub[:], unhex(v.pub)) {
+			return false
+		}
+	}
+	for _, v := range elligatorRevVectors {
+		var pub axlsign.PublicKey
+		copy(pub[:], unhex(v.pub))
+		var priv axlsign.PrivateKey
+		copy(priv[:], unhex(v.priv))
+		if priv.Public() != pub {
+			return false
+		}
+
+		// Both tweak bits, with and without padding.
+		for _, tweak := range []uint8{0x00, 0x41, 0x80, 0xc1} {
+			var want = unhex(v.rep0)
+			if tweak&1 == 1 {
+				want = unhex(v.rep1)
+			}
+			var rep, ok = axlsign.PublicKeyToRepresentative(pub, tweak)
+			if ok != (len(want) != 0) {
+				return false
+			}
+			if ok {
+				want[31] = want[31] | tweak&0xc0
+				if !bytes.Equal(rep[:], want) {
+					return false
+				}
+			}
+		}
+
+		// The same key from GenerateRepresentableKey, with no low-order
+		// component and obfs4's tweak bit. Without a representative it
+		// asks for another seed.
+		var seed = append(unhex(v.priv), v.bit)
+		seed[0] = seed[0] & 248
+		var gpub, gpriv, grep, err = axlsign.GenerateRepresentableKey(bytes.NewReader(seed))
+		if v.rep0 == "" {
+			if err == nil {
+				return false
+			}
+			continue
+		}
+		var want = unhex(v.rep0)
+		if v.bit == 1 {
+			want = unhex(v.rep1)
+		}
+		if err != nil || gpub != pub || gpriv.Public() != pub || !bytes.Equal(grep[:], want) {
+			return false
+		}
+	}
+	return true
+}
+
+// Checks that representatives map back to their public keys and that the
+// keys agree on a shared key with an ordinary key pair.
+func elligatorTest(priv axlsign.PrivateKey) bool {
+	var pub = priv.Public()
+	for i := 0; i < 8; i++ {
+		var epub, epriv, rep, err = axlsign.GenerateRepresentableKey(cryptorand.Reader)
+		if err != nil || axlsign.RepresentativeToPublicKey(rep) != epub {
+			return false
+		}
+		var peer = axlsign.RepresentativeToPublicKey(rep)
+		if !bytes.Equal(axlsign.SharedKey(priv[:], peer[:]), axlsign.SharedKey(epriv[:], pub[:])) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package main
+
+import "crypto"
+import "encoding/hex"
+import "strings"
+import "curve25519-go/axlsign"
+
+// RFC 9380 K.3: expand_message_xmd with SHA-512.
+var xmdVectors = []struct {
+	msg    string
+	length int
+	out    string
+}{
+	{"", 32,
+		"6b9a7312411d92f921c6f68ca0b6380730a1a4d982c507211a90964c394179ba"},
+	{"abc", 32,
+		"0da749f12fbe5483eb066a5f595055679b976e93abe9be6f0f6318bce7aca8dc"},
+	{"", 128,
+		"41b037d1734a5f8df225dd8c7de38f851efdb45c372887be655212d07251b921b052b62eaed99b46f72f2ef4cc96bfaf254ebbbec091e1a3b9e4fb5e5b619d2e0c5414800a1d882b62bb5cd1778f098b8eb6cb399d5d9d18f5d5842cf5d13d7eb00a7cff859b605da678b318bd0e65ebff70bec88c753b159a805d2c89c55961"},
+	{"abc", 128,
+		"7f1dddd13c08b543f2e2037b14cefb255b44c83cc397c1786d975653e36a6b11bdd7732d8b38adb4a0edc26a0cef4bb45217135456e58fbca1703cd6032cb1347ee720b87972d63fbf232587043ed2901bce7f22610c0419751c065922b488431851041310ad659e4b23520e1772ab29dcdeb2002222a363f0c2b1c972b3efe1"},
+}
+
+// RFC 9380 J.4 and J.5, with the DST QUUX-V01-CS02-with-<suite>. The
+// coordinates are big-endian as in the RFC; for curve25519 only the
+// u-coordinate is checked.
+var hashToCurveVectors = []struct {
+	curve string
+	ro    bool
+	msg   string
+	x, y  string
+}{
+	// edwards25519_XMD:SHA-512_ELL2_RO_
+	{"edwards25519", true, "",
+		"3c3da6925a3c3c268448dcabb47ccde5439559d9599646a8260e47b1e4822fc6",
+		"09a6c8561a0b22bef63124c588ce4c62ea83a3c899763af26d795302e115dc21"},
+	{"edwards25519", true, "abc",
+		"608040b42285cc0d72cbb3985c6b04c935370c7361f4b7fbdb1ae7f8c1a8ecad",
+		"1a8395b88338f22e435bbd301183e7f20a5f9de643f11882fb237f88268a5531"},
+	{"edwards25519", true, "abcdef0123456789",
+		"6d7fabf47a2dc03fe7d47f7dddd21082c5fb8f86743cd020f3fb147d57161472",
+		"53060a3d140e7fbcda641ed3cf42c88a75411e648a1add71217f70ea8ec561a6"},
+	{"edwards25519", true, "q128_" + strings.Repeat("q", 128),
+		"5fb0b92acedd16f3bcb0ef83f5c7b7a9466b5f1e0d8d217421878ea3686f8524",
+		"2eca15e355fcfa39d2982f67ddb0eea138e2994f5956ed37b7f72eea5e89d2f7"},
+	{"edwards25519", true, "a512_" + strings.Repeat("a", 512),
+		"0efcfde5898a839b00997fbe40d2ebe950bc81181afbd5cd6b9618aa336c1e8c",
+		"6dc2fc04f266c5c27f236a80b14f92ccd051ef1ff027f26a07f8c0f327d8f995"},
+	// edwards25519_XMD:SHA-512_ELL2_NU_
+	{"edwards25519", false, "",
+		"1ff2b70ecf862799e11b7ae744e3489aa058ce805dd323a936375a84695e76da",
+		"222e314d04a4d5725e9f2aff9fb2a6b69ef375a1214eb19021ceab2d687f0f9b"},
+	{"edwards25519", false, "abc",
+		"5f13cc69c891d86927eb37bd4afc6672360007c63f68a33ab423a3aa040fd2a8",
+		"67732d50f9a26f73111dd1ed5dba225614e538599db58ba30aaea1f5c827fa42"},
+	{"edwards25519", false, "abcdef0123456789",
+		"1dd2fefce934ecfd7aae6ec998de088d7dd03316aa1847198aecf699ba6613f1",
+		"2f8a6c24dd1adde73909cada6a4a137577b0f179d336685c4a955a0a8e1a86fb"},
+	{"edwards25519", false, "q128_" + strings.Repeat("q", 128),
+		"35fbdc5143e8a97afd3096f2b843e07df72e15bfca2eaf6879bf97c5d3362f73",
+		"2af6ff6ef5ebba128b0774f4296cb4c2279a074658b083b8dcca91f57a603450"},
+	{"edwards25519", false, "a512_" + strings.Repeat("a", 512),
+		"6e5e1f37e99345887fc12111575fc1c3e36df4b289b8759d23af14d774b66bff",
+		"2c90c3d39eb18ff291d33441b35f3262cdd307162cc97c31bfcc7a4245891a37"},
+	// curve25519_XMD:SHA-512_ELL2_RO_
+	{"curve25519", true, "", "2de3780abb67e861289f5749d16d3e217ffa722192d16bbd9d1bfb9d112b98c0", ""},
+	{"curve25519", true, "abc", "2b4419f1f2d48f5872de692b0aca72cc7b0a60915dd70bde432e826b6abc526d", ""},
+	{"curve25519", true, "abcdef0123456789", "68ca1ea5a6acf4e9956daa101709b1eee6c1bb0df1de3b90d4602382a104c036", ""},
+	{"curve25519", true, "q128_" + strings.Repeat("q", 128), "096e9c8bae6c06b554c1ee69383bb0e82267e064236b3a30608d4ed20b73ac5a", ""},
+	{"curve25519", true, "a512_" + strings.Repeat("a", 512), "1bc61845a138e912f047b5e70ba9606ba2a447a4dade024c8ef3dd42b7bbc5fe", ""},
+	// curve25519_XMD:SHA-512_ELL2_NU_
+	{"curve25519", false, "", "1bb913f0c9daefa0b3375378ffa534bda5526c97391952a7789eb976edfe4d08", ""},
+	{"curve25519", false, "abc", "7c22950b7d900fa866334262fcaea47a441a578df43b894b4625c9b450f9a026", ""},
+	{"curve25519", false, "abcdef0123456789", "31ad08a8b0deeb2a4d8b0206ca25f567ab4e042746f792f4b7973f3ae2096c52", ""},
+	{"curve25519", false, "q128_" + strings.Repeat("q", 128), "027877759d155b1997d0d84683a313eb78bdb493271d935b622900459d52ceaa", ""},
+	{"curve25519", false, "a512_" + strings.Repeat("a", 512), "5fd892c0958d1a75f54c3182a18d286efab784e774d1e017ba2fb252998b5dc1", ""},
+}
+
+// Returns the little-endian bytes of a big-endian hex field element.
+func unhexLE(s string) []uint8 {
+	var b = unhex(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+func hashToCurveTest() bool {
+	for _, v := range xmdVectors {
+		var out, err = axlsign.ExpandMessageXMD(crypto.SHA512, []uint8(v.msg),
+			[]uint8("QUUX-V01-CS02-with-expander-SHA512-256"), v.length)
+		if err != nil || hex.EncodeToString(out) != v.out {
+			return false
+		}
+	}
+
+	for _, v := range hashToCurveVectors {
+		var suite = "_XMD:SHA-512_ELL2_NU_"
+		if v.ro {
+			suite = "_XMD:SHA-512_ELL2_RO_"
+		}
+		var dst = []uint8("QUUX-V01-CS02-with-" + v.curve + suite)
+		var msg = []uint8(v.msg)
+
+		var want = unhexLE(v.x)
+		var got []uint8
+		if v.curve == "edwards25519" {
+			// The encoding is y with the sign of x in the top bit.
+			var x = want
+			want = unhexLE(v.y)
+			want[31] |= (x[0] & 1) << 7
+			var p, err = axlsign.EncodeToEdwards25519(msg, dst)
+			if v.ro {
+				p, err = axlsign.HashToEdwards25519(msg, dst)
+			}
+			if err != nil {
+				return false
+			}
+			got = p[:]
+		} else {
+			var p, err = axlsign.EncodeToCurve25519(msg, dst)
+			if v.ro {
+				p, err = axlsign.HashToCurve25519(msg, dst)
+			}
+			if err != nil {
+				return false
+			}
+			got = p[:]
+		}
+		if hex.EncodeToString(got) != hex.EncodeToString(want) {
+			return false
+		}
+	}
+
+	if _, err := axlsign.HashToEdwards25519([]uint8("abc"), nil); err == nil {
+		return false
+	}
+	return true
+}
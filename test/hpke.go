@@ -0,0 +1,269 @@
+package main
+
+import "bytes"
+import cryptorand "crypto/rand"
+import "encoding/hex"
+import "strconv"
+import "curve25519-go/axlsign"
+import "curve25519-go/hpke"
+
+// RFC 9180, appendix A.1 and A.2: DHKEM(X25519, HKDF-SHA256), HKDF-SHA256,
+// the encryptions with the sequence numbers in hpkeSeqs and the exports
+// with the contexts in hpkeExportContexts, for each mode.
+var hpkeVectors = []struct {
+	mode       int
+	aead       hpke.AEAD
+	info       string
+	ikmE, ikmR string
+	ikmS       string
+	psk, pskID string
+	enc        string
+	cts        [6]string
+	exported   [3]string
+}{
+	// RFC 9180 A.1.1
+	{0, hpke.AES128GCM, "4f6465206f6e2061204772656369616e2055726e",
+		"7268600d403fce431561aef583ee1613527cff655c1343f29812e66706df3234",
+		"6db9df30aa07dd42ee5e8181afdb977e538f5e1fec8a06223f33f7013e525037",
+		"",
+		"", "",
+		"37fda3567bdbd628e88668c3c8d7e97d1d1253b6d4ea6d44c150f741f1bf4431",
+		[6]string{
+			"f938558b5d72f1a23810b4be2ab4f84331acc02fc97babc53a52ae8218a355a96d8770ac83d07bea87e13c512a",
+			"af2d7e9ac9ae7e270f46ba1f975be53c09f8d875bdc8535458c2494e8a6eab251c03d0c22a56b8ca42c2063b84",
+			"498dfcabd92e8acedc281e85af1cb4e3e31c7dc394a1ca20e173cb72516491588d96a19ad4a683518973dcc180",
+			"583bd32bc67a5994bb8ceaca813d369bca7b2a42408cddef5e22f880b631215a09fc0012bc69fccaa251c0246d",
+			"7175db9717964058640a3a11fb9007941a5d1757fda1a6935c805c21af32505bf106deefec4a49ac38d71c9e0a",
+			"957f9800542b0b8891badb026d79cc54597cb2d225b54c00c5238c25d05c30e3fbeda97d2e0e1aba483a2df9f2",
+		},
+		[3]string{
+			"3853fe2b4035195a573ffc53856e77058e15d9ea064de3e59f4961d0095250ee",
+			"2e8f0b54673c7029649d4eb9d5e33bf1872cf76d623ff164ac185da9e88c21a5",
+			"e9e43065102c3836401bed8c3c3c75ae46be1639869391d62c61f1ec7af54931",
+		}},
+	// RFC 9180 A.1.2
+	{1, hpke.AES128GCM, "4f6465206f6e2061204772656369616e2055726e",
+		"78628c354e46f3e169bd231be7b2ff1c77aa302460a26dbfa15515684c00130b",
+		"d4a09d09f575fef425905d2ab396c1449141463f698f8efdb7accfaff8995098",
+		"",
+		"0247fd33b913760fa1fa51e1892d9f307fbe65eb171e8132c2af18555a738b82", "456e6e796e20447572696e206172616e204d6f726961",
+		"0ad0950d9fb9588e59690b74f1237ecdf1d775cd60be2eca57af5a4b0471c91b",
+		[6]string{
+			"e52c6fed7f758d0cf7145689f21bc1be6ec9ea097fef4e959440012f4feb73fb611b946199e681f4cfc34db8ea",
+			"49f3b19b28a9ea9f43e8c71204c00d4a490ee7f61387b6719db765e948123b45b61633ef059ba22cd62437c8ba",
+			"257ca6a08473dc851fde45afd598cc83e326ddd0abe1ef23baa3baa4dd8cde99fce2c1e8ce687b0b47ead1adc9",
+			"a71d73a2cd8128fcccbd328b9684d70096e073b59b40b55e6419c9c68ae21069c847e2a70f5d8fb821ce3dfb1c",
+			"55f84b030b7f7197f7d7d552365b6b932df5ec1abacd30241cb4bc4ccea27bd2b518766adfa0fb1b71170e9392",
+			"c5bf246d4a790a12dcc9eed5eae525081e6fb541d5849e9ce8abd92a3bc1551776bea16b4a518f23e237c14b59",
+		},
+		[3]string{
+			"dff17af354c8b41673567db6259fd6029967b4e1aad13023c2ae5df8f4f43bf6",
+			"6a847261d8207fe596befb52928463881ab493da345b10e1dcc645e3b94e2d95",
+			"8aff52b45a1be3a734bc7a41e20b4e055ad4c4d22104b0c20285a7c4302401cd",
+		}},
+	// RFC 9180 A.1.3
+	{2, hpke.AES128GCM, "4f6465206f6e2061204772656369616e2055726e",
+		"6e6d8f200ea2fb20c30b003a8b4f433d2f4ed4c2658d5bc8ce2fef718059c9f7",
+		"f1d4a30a4cef8d6d4e3b016e6fd3799ea057db4f345472ed302a67ce1c20cdec",
+		"94b020ce91d73fca4649006c7e7329a67b40c55e9e93cc907d282bbbff386f58",
+		"", "",
+		"23fb952571a14a25e3d678140cd0e5eb47a0961bb18afcf85896e5453c312e76",
+		[6]string{
+			"5fd92cc9d46dbf8943e72a07e42f363ed5f721212cd90bcfd072bfd9f44e06b80fd17824947496e21b680c141b",
+			"d3736bb256c19bfa93d79e8f80b7971262cb7c887e35c26370cfed62254369a1b52e3d505b79dd699f002bc8ed",
+			"122175cfd5678e04894e4ff8789e85dd381df48dcaf970d52057df2c9acc3b121313a2bfeaa986050f82d93645",
+			"dae12318660cf963c7bcbef0f39d64de3bf178cf9e585e756654043cc5059873bc8af190b72afc43d1e0135ada",
+			"55d53d85fe4d9e1e97903101eab0b4865ef20cef28765a47f840ff99625b7d69dee927df1defa66a036fc58ff2",
+			"42fa248a0e67ccca688f2b1d13ba4ba84755acf764bd797c8f7ba3b9b1dc3330326f8d172fef6003c79ec72319",
+		},
+		[3]string{
+			"28c70088017d70c896a8420f04702c5a321d9cbf0279fba899b59e51bac72c85",
+			"25dfc004b0892be1888c3914977aa9c9bbaf2c7471708a49e1195af48a6f29ce",
+			"5a0131813abc9a522cad678eb6bafaabc43389934adb8097d23c5ff68059eb64",
+		}},
+	// RFC 9180 A.1.4
+	{3, hpke.AES128GCM, "4f6465206f6e2061204772656369616e2055726e",
+		"4303619085a20ebcf18edd22782952b8a7161e1dbae6e46e143a52a96127cf84",
+		"4b16221f3b269a88e207270b5e1de28cb01f847841b344b8314d6a622fe5ee90",
+		"62f77dcf5df0dd7eac54eac9f654f426d4161ec850cc65c54f8b65d2e0b4e345",
+		"0247fd33b913760fa1fa51e1892d9f307fbe65eb171e8132c2af18555a738b82", "456e6e796e20447572696e206172616e204d6f726961",
+		"820818d3c23993492cc5623ab437a48a0a7ca3e9639c140fe1e33811eb844b7c",
+		[6]string{
+			"a84c64df1e11d8fd11450039d4fe64ff0c8a99fca0bd72c2d4c3e0400bc14a40f27e45e141a24001697737533e",
+			"4d19303b848f424fc3c3beca249b2c6de0a34083b8e909b6aa4c3688505c05ffe0c8f57a0a4c5ab9da127435d9",
+			"0c085a365fbfa63409943b00a3127abce6e45991bc653f182a80120868fc507e9e4d5e37bcc384fc8f14153b24",
+			"000a3cd3a3523bf7d9796830b1cd987e841a8bae6561ebb6791a3f0e34e89a4fb539faeee3428b8bbc082d2c1a",
+			"576d39dd2d4cc77d1a14a51d5c5f9d5e77586c3d8d2ab33bdec6379e28ce5c502f0b1cbd09047cf9eb9269bb52",
+			"13239bab72e25e9fd5bb09695d23c90a24595158b99127505c8a9ff9f127e0d657f71af59d67d4f4971da028f9",
+		},
+		[3]string{
+			"08f7e20644bb9b8af54ad66d2067457c5f9fcb2a23d9f6cb4445c0797b330067",
+			"52e51ff7d436557ced5265ff8b94ce69cf7583f49cdb374e6aad801fc063b010",
+			"a30c20370c026bbea4dca51cb63761695132d342bae33a6a11527d3e7679436d",
+		}},
+	// RFC 9180 A.2.1
+	{0, hpke.ChaCha20Poly1305, "4f6465206f6e2061204772656369616e2055726e",
+		"909a9b35d3dc4713a5e72a4da274b55d3d3821a37e5d099e74a647db583a904b",
+		"1ac01f181fdf9f352797655161c58b75c656a6cc2716dcb66372da835542e1df",
+		"",
+		"", "",
+		"1afa08d3dec047a643885163f1180476fa7ddb54c6a8029ea33f95796bf2ac4a",
+		[6]string{
+			"1c5250d8034ec2b784ba2cfd69dbdb8af406cfe3ff938e131f0def8c8b60b4db21993c62ce81883d2dd1b51a28",
+			"6b53c051e4199c518de79594e1c4ab18b96f081549d45ce015be002090bb119e85285337cc95ba5f59992dc98c",
+			"71146bd6795ccc9c49ce25dda112a48f202ad220559502cef1f34271e0cb4b02b4f10ecac6f48c32f878fae86b",
+			"63357a2aa291f5a4e5f27db6baa2af8cf77427c7c1a909e0b37214dd47db122bb153495ff0b02e9e54a50dbe16",
+			"18ab939d63ddec9f6ac2b60d61d36a7375d2070c9b683861110757062c52b8880a5f6b3936da9cd6c23ef2a95c",
+			"7a4a13e9ef23978e2c520fd4d2e757514ae160cd0cd05e556ef692370ca53076214c0c40d4c728d6ed9e727a5b",
+		},
+		[3]string{
+			"4bbd6243b8bb54cec311fac9df81841b6fd61f56538a775e7c80a9f40160606e",
+			"8c1df14732580e5501b00f82b10a1647b40713191b7c1240ac80e2b68808ba69",
+			"5acb09211139c43b3090489a9da433e8a30ee7188ba8b0a9a1ccf0c229283e53",
+		}},
+	// RFC 9180 A.2.2
+	{1, hpke.ChaCha20Poly1305, "4f6465206f6e2061204772656369616e2055726e",
+		"35706a0b09fb26fb45c39c2f5079c709c7cf98e43afa973f14d88ece7e29c2e3",
+		"26b923eade72941c8a85b09986cdfa3f1296852261adedc52d58d2930269812b",
+		"",
+		"0247fd33b913760fa1fa51e1892d9f307fbe65eb171e8132c2af18555a738b82", "456e6e796e20447572696e206172616e204d6f726961",
+		"2261299c3f40a9afc133b969a97f05e95be2c514e54f3de26cbe5644ac735b04",
+		[6]string{
+			"4a177f9c0d6f15cfdf533fb65bf84aecdc6ab16b8b85b4cf65a370e07fc1d78d28fb073214525276f4a89608ff",
+			"5c3cabae2f0b3e124d8d864c116fd8f20f3f56fda988c3573b40b09997fd6c769e77c8eda6cda4f947f5b704a8",
+			"14958900b44bdae9cbe5a528bf933c5c990dbb8e282e6e495adf8205d19da9eb270e3a6f1e0613ab7e757962a4",
+			"c2a7bc09ddb853cf2effb6e8d058e346f7fe0fb3476528c80db6b698415c5f8c50b68a9a355609e96d2117f8d3",
+			"2414d0788e4bc39a59a26d7bd5d78e111c317d44c37bd5a4c2a1235f2ddc2085c487d406490e75210c958724a7",
+			"c567ae1c3f0f75abe1dd9e4532b422600ed4a6e5b9484dafb1e43ab9f5fd662b28c00e2e81d3cde955dae7e218",
+		},
+		[3]string{
+			"813c1bfc516c99076ae0f466671f0ba5ff244a41699f7b2417e4c59d46d39f40",
+			"2745cf3d5bb65c333658732954ee7af49eb895ce77f8022873a62a13c94cb4e1",
+			"ad40e3ae14f21c99bfdebc20ae14ab86f4ca2dc9a4799d200f43a25f99fa78ae",
+		}},
+	// RFC 9180 A.2.3
+	{2, hpke.ChaCha20Poly1305, "4f6465206f6e2061204772656369616e2055726e",
+		"938d3daa5a8904540bc24f48ae90eed3f4f7f11839560597b55e7c9598c996c0",
+		"64835d5ee64aa7aad57c6f2e4f758f7696617f8829e70bc9ac7a5ef95d1c756c",
+		"9d8f94537d5a3ddef71234c0baedfad4ca6861634d0b94c3007fed557ad17df6",
+		"", "",
+		"f7674cc8cd7baa5872d1f33dbaffe3314239f6197ddf5ded1746760bfc847e0e",
+		[6]string{
+			"ab1a13c9d4f01a87ec3440dbd756e2677bd2ecf9df0ce7ed73869b98e00c09be111cb9fdf077347aeb88e61bdf",
+			"3265c7807ffff7fdace21659a2c6ccffee52a26d270c76468ed74202a65478bfaedfff9c2b7634e24f10b71016",
+			"3aadee86ad2a05081ea860033a9d09dbccb4acac2ded0891da40f51d4df19925f7a767b076a5cbc9355c8fd35e",
+			"502ecccd5c2be3506a081809cc58b43b94f77cbe37b8b31712d9e21c9e61aa6946a8e922f54eae630f88eb8033",
+			"652e597ba20f3d9241cda61f33937298b1169e6adf72974bbe454297502eb4be132e1c5064702fc165c2ddbde8",
+			"3be14e8b3bbd1028cf2b7d0a691dbbeff71321e7dec92d3c2cfb30a0994ab246af76168480285a60037b4ba13a",
+		},
+		[3]string{
+			"070cffafd89b67b7f0eeb800235303a223e6ff9d1e774dce8eac585c8688c872",
+			"2852e728568d40ddb0edde284d36a4359c56558bb2fb8837cd3d92e46a3a14a8",
+			"1df39dc5dd60edcbf5f9ae804e15ada66e885b28ed7929116f768369a3f950ee",
+		}},
+	// RFC 9180 A.2.4
+	{3, hpke.ChaCha20Poly1305, "4f6465206f6e2061204772656369616e2055726e",
+		"49d6eac8c6c558c953a0a252929a818745bb08cd3d29e15f9f5db5eb2e7d4b84",
+		"f3304ddcf15848488271f12b75ecaf72301faabf6ad283654a14c398832eb184",
+		"20ade1d5203de1aadfb261c4700b6432e260d0d317be6ebbb8d7fffb1f86ad9d",
+		"0247fd33b913760fa1fa51e1892d9f307fbe65eb171e8132c2af18555a738b82", "456e6e796e20447572696e206172616e204d6f726961",
+		"656a2e00dc9990fd189e6e473459392df556e9a2758754a09db3f51179a3fc02",
+		[6]string{
+			"9aa52e29274fc6172e38a4461361d2342585d3aeec67fb3b721ecd63f059577c7fe886be0ede01456ebc67d597",
+			"59460bacdbe7a920ef2806a74937d5a691d6d5062d7daafcad7db7e4d8c649adffe575c1889c5c2e3a49af8e3e",
+			"5688ff6a03ba26ae936044a5c800f286fb5d1eccdd2a0f268f6ff9773b51169318d1a1466bb36263415071db00",
+			"d936b7a01f5c7dc4c3dc04e322cc694684ee18dd71719196874e5235aed3cfb06cadcd3bc7da0877488d7c551d",
+			"4d4c462f7b9b637eaf1f4e15e325b7bc629c0af6e3073422c86064cc3c98cff87300f054fd56dd57dc34358beb",
+			"9b7f84224922d2a9edd7b2c2057f3bcf3a547f17570575e626202e593bfdd99e9878a1af9e41ded58c7fb77d2f",
+		},
+		[3]string{
+			"c23ebd4e7a0ad06a5dddf779f65004ce9481069ce0f0e6dd51a04539ddcbd5cd",
+			"ed7ff5ca40a3d84561067ebc8e01702bc36cf1eb99d42a92004642b9dfaadd37",
+			"d3bae066aa8da27d527d85c040f7dd6ccb60221c902ee36a82f70bcd62a60ee4",
+		}},
+}
+
+// The sequence numbers of the encryptions listed in RFC 9180. The
+// additional data is "Count-" followed by the sequence number.
+var hpkeSeqs = [6]int{0, 1, 2, 4, 255, 256}
+
+const hpkePlaintext = "4265617574792069732074727574682c20747275746820626561757479"
+
+// The exporter contexts of RFC 9180, each with a length of 32.
+var hpkeExportContexts = [3]string{"", "00", "54657374436f6e74657874"}
+
+func unhex(s string) []uint8 {
+	var b, _ = hex.DecodeString(s)
+	return b
+}
+
+func hpkeTest() bool {
+	for _, v := range hpkeVectors {
+		var suite = hpke.Suite{KDF: hpke.HKDFSHA256, AEAD: v.aead}
+		var pkR, skR = hpke.DeriveKeyPair(unhex(v.ikmR))
+		var pkS, skS = hpke.DeriveKeyPair(unhex(v.ikmS))
+		var info, psk, pskID = unhex(v.info), unhex(v.psk), unhex(v.pskID)
+		var rand = bytes.NewReader(unhex(v.ikmE))
+
+		var enc []uint8
+		var sender, receiver *hpke.Context
+		var errS, errR error
+		switch v.mode {
+		case 0:
+			enc, sender, errS = hpke.SetupBaseS(suite, rand, pkR, info)
+			receiver, errR = hpke.SetupBaseR(suite, enc, skR, info)
+		case 1:
+			enc, sender, errS = hpke.SetupPSKS(suite, rand, pkR, info, psk, pskID)
+			receiver, errR = hpke.SetupPSKR(suite, enc, skR, info, psk, pskID)
+		case 2:
+			enc, sender, errS = hpke.SetupAuthS(suite, rand, pkR, info, skS)
+			receiver, errR = hpke.SetupAuthR(suite, enc, skR, info, pkS)
+		case 3:
+			enc, sender, errS = hpke.SetupAuthPSKS(suite, rand, pkR, info, psk, pskID, skS)
+			receiver, errR = hpke.SetupAuthPSKR(suite, enc, skR, info, psk, pskID, pkS)
+		}
+		if errS != nil || errR != nil || !bytes.Equal(enc, unhex(v.enc)) {
+			return false
+		}
+
+		// Seal and open every sequence number up to the last listed one.
+		var pt = unhex(hpkePlaintext)
+		var next = 0
+		for seq := 0; seq <= hpkeSeqs[len(hpkeSeqs)-1]; seq++ {
+			var aad = []uint8("Count-" + strconv.Itoa(seq))
+			var ct, _ = sender.Seal(aad, pt)
+			var opened, err = receiver.Open(aad, ct)
+			if err != nil || !bytes.Equal(opened, pt) {
+				return false
+			}
+			if seq == hpkeSeqs[next] {
+				if !bytes.Equal(ct, unhex(v.cts[next])) {
+					return false
+				}
+				next++
+			}
+		}
+
+		for i, ctx := range hpkeExportContexts {
+			var exportedS, _ = sender.Export(unhex(ctx), 32)
+			var exportedR, _ = receiver.Export(unhex(ctx), 32)
+			if !bytes.Equal(exportedS, unhex(v.exported[i])) || !bytes.Equal(exportedR, exportedS) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Auth mode with an axlsign signing key as the sender key.
+func hpkeAuthTest(skS axlsign.PrivateKey, pkR axlsign.PublicKey, skR axlsign.PrivateKey, msg []uint8) bool {
+	var suite = hpke.Suite{KDF: hpke.HKDFSHA256, AEAD: hpke.ChaCha20Poly1305}
+	var enc, sender, _ = hpke.SetupAuthS(suite, cryptorand.Reader, pkR, nil, skS)
+	var ct, _ = sender.Seal(nil, msg)
+	var receiver, err = hpke.SetupAuthR(suite, enc, skR, nil, skS.Public())
+	if err != nil {
+		return false
+	}
+	var pt, oerr = receiver.Open(nil, ct)
+	return oerr == nil && bytes.Equal(pt, msg)
+}
@@ -0,0 +1,631 @@
+package main
+
+import "bytes"
+import "encoding/hex"
+import "strconv"
+import "curve25519-go/axlsign"
+import "curve25519-go/noise"
+
+// Noise vectors from flynn/noise's vectors.txt, the entries with prologue
+// "notsecret" and non-empty payloads: every handshake message, then every
+// transport message, alternately from the initiator and the responder.
+// psk is the pskN placement, or -1 without a pre-shared key.
+//
+// The payloads are the same in every entry, see noisePayload. These
+// vectors stand in for cacophony's, which cover the same patterns and
+// cipher suites.
+var noiseVectors = []struct {
+	pattern noise.HandshakePattern
+	psk     int
+	suite   noise.CipherSuite
+	msgs    []string
+}{
+	{noise.HandshakeNN, -1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254746573745f6d73675f30",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484669274a4f99ffbbcd930fb9d5f607de66556bd116615a94643140d",
+		"e693375ca5a2a0ff37a4b36662433ecc789e8a04887751ac3b0bb070039726",
+		"821cbd91e90a763bc70ac3cdee3bd2fb4b9dcd0e7cc3a066b85811c0c55c10",
+	}},
+	{noise.HandshakeNN, -1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254746573745f6d73675f30",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466bb598b7e636e9475d9a74243a419c31324b40cc77cc7a7ea3b24",
+		"96cd46be111804586a935795eeb4ce62bdec121048a10520b00266b22722eb",
+		"fe2bc534e31964c0bd56337223e921565e39dbc5f156aa04766ced4689a2a2",
+	}},
+	{noise.HandshakeNN, -1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254746573745f6d73675f30",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d4846626cb189266923cb8ce8e3fc80aa75d92678f6bfe13be7ff6aed1",
+		"0909e697fc6dca9ffceffebee77c39187c353d4256d66f6d42ff5d6a8b5bcd",
+		"0770576631856631b449818953d4252baf1cf8d49d718ce220b9173567fc97",
+	}},
+	{noise.HandshakeNN, -1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254746573745f6d73675f30",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484663d8d136c2fcf7ecd3c3d4c93591205092db481f2a901eb96f06c",
+		"a0193b62b90fb3497108ec8adcc340a49ebb0a07f1654d71f7e38361f57ba5",
+		"b2afdcb051e896fa5b6a23def5ee6bdd6032f1b39b2d22ef7da01857648389",
+	}},
+	{noise.HandshakeNN, 0, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254b8766d12729c594966e9df5831055ca8c424d8ca8f3f2a6fbeac",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d4846633188335572849c06f2123581c51160861c0049f3bb291bd9e3f",
+		"45229f0fb23ccd92b0554c5be976ab8ccecf5f1e7503af4c5a1e4e45d35dd5",
+		"fcf39b68313e893f9682801d60aee12337d52a64661af37a0366b7924d1657",
+	}},
+	{noise.HandshakeNN, 0, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662547c78f22f8cea986f934ab17c2484a24a990a6473d588a4f20e99",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484666913ea64c74c2f63ee5e32a5358320d459322d624c9ccc975fa0",
+		"b349a522c145762c7c737ac1d1425ce1fb25c7cca626177ee4ceed3cd6fb3d",
+		"b41e24399dc3f1ad2faf82868700e4bf31bb89f6616e1d6a92802bb8ad80d6",
+	}},
+	{noise.HandshakeNN, 0, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd16625499813d4f7cdbccb39053c90fa0232673ba28f11c1e925324c845",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484665a507bc4cb8222edadcb8a3c7dd94841834ec807680c5446d280",
+		"7bb01e54840881ef4911b030602c665e4799652c4260b32f67119716cf2dac",
+		"63c4c131b3c079eb101cf9cd03627e5d50e693513402efb26d5f46d62ba1e0",
+	}},
+	{noise.HandshakeNN, 0, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254d2f8054fcaf80f347006e0fc25590a31fd33c4626fe59283ea40",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466e21a3177614fce09f014af55e853ed6b88b0e4628e071b23e905",
+		"6a7b199c69a64cc2ea3c556cf17489fd2ae452d3f3c2a0871cebd327fc31c6",
+		"e58d43e0c69d8c15df523586b2c58ca40cb0472b5b3775f1cca807fee28a71",
+	}},
+	{noise.HandshakeNN, 1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254c65e819d0b4074ef00531acf9a294d769a2eae079b342632d219",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466da135105806675a56c116abd2f66bf544bd00009d1f973865921",
+		"ff900c6284287621348a3f116e7d1cb4fa64dffb7904a8332b36377381eafd",
+		"d49b01235ee41b7bb4b32de0b258be2280dcf68262b690fef7f0511bdb52d1",
+	}},
+	{noise.HandshakeNN, 1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254d14f69b3b9d75ea1662d338ce00a4c4454f04db11957c29b45ba",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466961705fd812d198153745358e4e1e28b5a350b1f7abd0f271620",
+		"75c86e1615188d3feae514a908523ccbbce6f0b0fa368c0dbac6ddf01b6571",
+		"3b27b0caca5899086a3ebfb80a0651a18f1af75c9f71f3b3818a0170c8e615",
+	}},
+	{noise.HandshakeNN, 1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254294dfe9247d7b2223638957faf2dd1bd18941b394140015d37ed",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d4846638d4e172b074863b2c8e80ee9b319d2677512167577c8d105ecb",
+		"8617a57d1a212d915a093ba691465321b3a5e83a2a09334bc17a66e07f340b",
+		"bbddd9a51a1fe99634ea09790bfab387047c0bee2b540cbaa009e4b33f547d",
+	}},
+	{noise.HandshakeNN, 1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254422cb9f10bb05f92da1086ba7a3f7fdeede2360802fce2bab641",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466d48f5698c6af1a7d6f1cce81b2d248ccdd0f13d7c85b6b61d30a",
+		"84a7c955143ce45834b0acdef085054ab1a321668d7045dafc67b3e189f66d",
+		"44cb770629debcb89480285522a1fd693b666884f7758f6f864c09c538c119",
+	}},
+	{noise.HandshakeNN, 2, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662549c5cfd6799fc937000b54a31dc936d6b1bd4e8676cdcc42b1bf3",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484668a48263708b02a2508c97593c7ffd766732eba2768eab8536d6f",
+		"98fd5ab58a3ad920b59dd500179a238f715af6d3b0d6d4a1801fad5e3c8dc5",
+		"4deac6ad723c4459d22e13e42c68b1b87c98cea7c470d684acd1c238be8398",
+	}},
+	{noise.HandshakeNN, 2, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254ba71add3db8e76dddcfe83087861219e26e6f5b0b682e6758cdd",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484665aeaba3e8ae057890d221b12297799709eb36b11f4fd7366a4b2",
+		"eb1a9999225c8a03f6c053d24da26df330c7b2ac6315c5aeff9a4c2893d9e3",
+		"17aecb7454199636aa7e8a44b7e51b5dc6729b39faddd2739bc66d8cbf07be",
+	}},
+	{noise.HandshakeNN, 2, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662541b462c2b118f585719b4d2c84f08adfde3c8f24735ec8df3c5d7",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484664f09893c38c139765e44f2d6a49210f05ada099fb05937f7aa5b",
+		"a924b18a801dad51bf702aa50f87d8f3e2d3e54cb76c94493f5305768f5f64",
+		"0c94546ec021695355755f7e82ce1a12679f8df2d5a5b6e533c4cdc645a5f9",
+	}},
+	{noise.HandshakeNN, 2, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662547bddd25c5d196f5110f0e47e04cd720aa46674d274f35cc9219a",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466ad1970fd7307e7594135c3fbe2866e29c265002153e2342cf6a0",
+		"84c7ad0cff2af00d6c12896f0230233a99e1abeaef043747035d9a38c06f9e",
+		"4bdd4800b3abd620ce9f4c519428acf7912af8b6507aa3befecce2444d2614",
+	}},
+	{noise.HandshakeNK, -1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254bc7e9bcabcd39b9278b37f9892f7dec16e155389121da24e1fad",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466060fcddff00afaa37fd11c440d18031d7f9a735d2dd1ea6bfe24",
+		"56a475d3db0d0d5931542a93e3cd57c7dc51b29fc6d0a7cea41aea05d99fe5",
+		"5c239eb65b5f0d0641f6c6c20aec65646626249f9194e4211a2f8e761c2d72",
+	}},
+	{noise.HandshakeNK, -1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662543e44c6b6a0a9a28f5dafb35dfe4f2cf52995fadd57f0a4006d1c",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484666e1a02e46e9053fa2a81414fd4a5bd34dbd73cb3a6e1b896bce6",
+		"9cfd3ddea89d9f445475098f834e572ec4a8c5e9be740dd92831ef6cf6fd9e",
+		"5db2eb7c7b37b33cd42fd321e05d9048c9be3efa0ae3a8c76724307e7562ff",
+	}},
+	{noise.HandshakeNK, -1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254bdf08b60ecbebcc2f5066ba2dc101956c40473b8c6dfbc24f58b",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466dacd38c1ad625caa0a4702d85babf3841256b5660d3228dc121c",
+		"a6f4e53c8e82abdc1877317ee614b2cf1c36694a48536e932f5d5970709c23",
+		"4d08651c70754decf41c8b79d1fe8e8da60cdf64cbb521ab1f5be171617988",
+	}},
+	{noise.HandshakeNK, -1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662546cfcd5c91dd95543a2363b9bd07c092d8fff14687e5f48b43afc",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466b3f3dd3e34414275ad73c9d7e1d03e86e1580404241350ed9ab1",
+		"95922788fcef822a17b42f450fa14d05d8e6a4377ca0aea3b4804f03db74a2",
+		"0976cd4a786c253b37489b6bc3867b2df0dddf9f939b218da54092c6d3eca4",
+	}},
+	{noise.HandshakeNK, 0, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662544e94ec05dc68a1daa22de47ad3b7f3e2e17d0b302fa6890ea67f",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466281b734b3aba9427cddf3c94b9f572f110a02b5e52acab758e34",
+		"a6593eb8dd060da72b612dd4128b4fef345d58c2e8cc2764dff574a3094fe9",
+		"b7ce16bfa101adc89a7773ffd08c545634f59d9035cabadef5f5ce941351d4",
+	}},
+	{noise.HandshakeNK, 0, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254ca3238dddb5256cd690ae943692a4c055f22d3dd834ea90edfd8",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484660aa92dceb01712153f8d214f8f71c03c898cbd891e751f10d132",
+		"01d0ab0f394923f44c3abad69154757bbf902c64c5219bf8c624d69b11c959",
+		"2c2a431db9e64c43b6c0a520547bdd8e1368358c099345ab4969f1bb4a9299",
+	}},
+	{noise.HandshakeNK, 0, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662548bf52c4caaaaacfe7a71f5e1496c23adbee3020d4eb45211637a",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d4846694cf846b0941f8f8a463cfc791d89367bcce3320c4f0460a91b9",
+		"e73eb987000909cc3ce02566091760e69f524f23372277fbd94d2b335ee020",
+		"0e503113b6f99ce899fee344347bcc59165d827f4982c9a0af6f53c45608ce",
+	}},
+	{noise.HandshakeNK, 0, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd16625485932c8c7615c82637987b6d1508724221d9ac49e27a147f5b20",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466cce9cb21a513f9de326ccb24b4012111db3db7d41383ad139bf4",
+		"093acd47149fadf3574dd440428181edf9c61cc4a1b5ef815e8b779f1bbf40",
+		"2b8e170039917a61d4fe8acbd2147d50afafc32070458b51b666225614f364",
+	}},
+	{noise.HandshakeNK, 1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254b844c29336d91edab89a43bfc3fe933ccd161c24839025e6c72d",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484660e1784da1b96e7a4a7d743449335246823acd49e72750ccfa6fa",
+		"2e97c7a30baa66eb2eef35ac21f0985cb7139c68c681119ac87dd8864ab50e",
+		"2d441a28146d89109afcebf7fb254715a4937c1b775ed048735c301b7c15ba",
+	}},
+	{noise.HandshakeNK, 1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254b74e16d251935c4ba86516dfd74e045ff1c291281bcaa9362d00",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466668442074729cbc1afddf5f76f8ec753aa54926873c9096709c3",
+		"7d7766291245b52e9d504ca48bc3fb1118bdf46179c1b9bd32c925493533b3",
+		"11a8d0014a1f8b258deb81bed19ea97bec009031d6a7a374eba5528490926c",
+	}},
+	{noise.HandshakeNK, 1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd16625437ad3a66b77c777309c5d7683c6082c82b5adb434c70325029cc",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484662e4888b3081c3c5f1982d5856d4940dbdd17c07b285e331dce60",
+		"f3415ea0c9132c69c06f29873d9192a0910a6763addfc4e306b13c4e06c57c",
+		"c61e0bcc08bc9e2571eabbd48f192ecc3e846463f3b05c4cae6ffa956dce72",
+	}},
+	{noise.HandshakeNK, 1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662543f74b70b971271ffb3ef260b21a3f29655bee689e501c2a16b89",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484669920e429a6643b44e75f92aa79146466904a0217560ee27b49df",
+		"51fd5d7489dae3d6a99766db0afe89c1d19ed91a80b1bb64f94e747360fd2c",
+		"be24f94a04505c8ab51768a80b388f2758ddab3b2fa3eebfeaceeff0130d78",
+	}},
+	{noise.HandshakeNK, 2, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662541c1384003bc26279ca3cb125ee90cc218132fdbf93455a891a92",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484663780ee06ece35bbfb120dda59c0d7f0226547003e9db0016a4ab",
+		"93bb4e3dd1995295277446d3010fc7299dd2d1f283d7ce9ee8934d1caa60ef",
+		"d7ce2e01658cebe3086b25ae67c184cbb26e4855bc9c03a82c149948ea9a4e",
+	}},
+	{noise.HandshakeNK, 2, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662545b3af61cb5aa81f19c8e33d34af062c6a72f793a6612ed12887f",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466bf9e6c127466353179c7c97611f0c4ac0ac3142512e76f650851",
+		"6647abf5c995fb4b851bfd63c8e699286071c1fc2559764335c6329e2bea0f",
+		"89e20c2dca7e4c2202aa731271c5d2081164c86e7b365ca98465961e7113a6",
+	}},
+	{noise.HandshakeNK, 2, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662546f881ee2ccd20f3d6a74cad4c7694924e6494b10399373d97cd1",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466cc2683e6a737d2a339a62c5b55ccd5da93091e0295f94ae259b7",
+		"ed4dfff4fbbf57c63acf124128f12d3323706cd4212796f13ba3391f83e249",
+		"8009206bac08e514df2c83e3238bf92c5dc209430cbecb936b1a93813de1dc",
+	}},
+	{noise.HandshakeNK, 2, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254537ac869369393768b21c12506b70b078d6cb28378d02e8d93af",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d4846695f32217406ccaa2da8ffcd2908a04cb425c65daad407f91f131",
+		"d848f074d3d766c1a7770c51ceba699a16ad262790fc279e7dd2fcccfd4dca",
+		"51f74c4a80c3768dd6476fbb9c599efe5491567af3d18c8415d9d017821004",
+	}},
+	{noise.HandshakeXX, -1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254746573745f6d73675f30",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466c7f9c130891d2fcc2454ad9808ce708c7fde0ef21e72e985c38a6ed8cdaadcd9e07ed4c7d77e83b721e41d9bb2a8b57761f5532ce998f718c56f18083ab9e2f47c3f7f545a5eabbc4ece",
+		"e42e3908de4cd096b8b86320dfe9d03127451fdbfc423fd9ef86b4659fae03c897f77a2af21f5ce18cde8740fe9e5912f6cfb3372d0d7f9f5da0d9be88017bb339b951c56929f77fe9d6",
+		"7086fc0466ee7523680d09ff7c272e2a2817a6e2d6c4ec1c209506506e8957",
+		"e3beadf28ea871a3be666f43eaf457d030e538eb371ba48076a7db36a9a1bf",
+	}},
+	{noise.HandshakeXX, -1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254746573745f6d73675f30",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484663414af878d3e46a2f58911a816d6e8346d4ea17a6f2a0bb4ef4ed56c133cff4545958c588d17d6373e0c1dcfa3755d37f50cbca216483ac56bcc98f5095870aa814ba40c08079c11f087",
+		"87f864c11ba449f46a0a4f4e2eacbb7b0457784f4fca1937f572c93603e9c4d9c1e9a1a313d02b78871cfd178a521a4c7c7377a2f4f9144b2f0ccedc84d379151b466741e4b266db6023",
+		"a52ef02ba60e12696d1d6b9ef4245c88fca757b6134ad6e76b56e310a6adf6",
+		"2445aa438ebd649281c636cc7269ca82f1d9023d72520943aeabf909cdf521",
+	}},
+	{noise.HandshakeXX, -1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254746573745f6d73675f30",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466c558f251b38f5770b20bfe770709ec1aa6e0aa1a2d8b4485e51667a91055ceed9c32712c57e5aa04f65932b60b4c6064843c6dd463d2f588ba128cd76050bb6209711df3294879ad0e11",
+		"c0eef7241004fcad6fb84daa25d9a8921a8da60da9b8b39f387667e98069e72f9bd63447f97b7741e373ebbf9015ddd9427be5ec64387fbf2f98ea4a70997c58ecb2fe807114cabb46ae",
+		"bb9dd5494e382306a88f8f32a4bb268cad2632353dd13aad364dc7493c4561",
+		"5e5ac356a3234cee842c6f719fa0657d35b69bcfe51e2edf5534c4276b7131",
+	}},
+	{noise.HandshakeXX, -1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254746573745f6d73675f30",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484665393019dbd6f438795da206db0886610b26108e424142c2e9b5fd1f7ea70cde847f6866f15c3cd3f864f7ed682f1711a4917917195c8cf360e080035dfa88af5c6e9b820278e6016f7d7",
+		"e610eadc4b00c17708bf223f29a66f02342fbedf6c0044736544b9271821ae403bbe475185a4a265a50e1d43bdaeee7fe070c07602c6b84d25a3b4064af5be30115a052069038f5002a3",
+		"9ea1da1ec3bfecfffab213e537ed1791bfa887dd9c631351b3f63d6315ab9a",
+		"217c5111fad7afde33bd28abaff3def88a57ab50515115d23a10f28621f842",
+	}},
+	{noise.HandshakeXX, 0, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254f53528fc2e5e3678841db24a2abbe656a347e2116aab72adcf37",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466c0b9b0b4eee99d0667e049946fdcf25326ac013cdc4b28db9729186ee6609fb92e9751b1eacd771ac88dc4c7d4efb1c27a5cc2fc30fc4ce181878bc63aff8a4baf6b4ad054cbff26334a",
+		"612715579e991a6981e74d1e0bfd18932aa28f66fe336867c6d3174d3c4bf4afdf33b3790ad59aa73526df9169e40433370a74a98c31960c68493f3d69a2ca0c03be3bf415520e40ce31",
+		"c7241ad514c2c32d238263e4d45ba84d20cbe01ff02dda02e5a77bcb058e23",
+		"8faddc8172ce807cf234a378a2fd7524446ea3aad4864580d126a4c3852b9d",
+	}},
+	{noise.HandshakeXX, 0, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662547f533cf20723ac4407c87f43dc5dc6f6d1867a322a706af81adb",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d4846622df46c0ac1e0fc71795a84e37cc0e963d131c8e84c02cd5cfcfe8def3fe128b59d623ffc18ae67b1acba43eb87910b02dc6c1642ae7e8ae8c8e861ed15d8d6b65ff99f73d8286cc9819",
+		"462127adbe047db3d1fce0581b5447d99b606c591545a7719132e0c91fe93d1294b31017013e8ac0a697b42922a5fe204111b0bfa4fdeb4704a4b5492137b40088f810ee4d1a58882e25",
+		"75fff8afebd2f14da1cac9cc5b5201395cdf2ad65f3a97804e360c16f4e2ac",
+		"150cc85f79f9ca0f0730b8b4707805ed1969ff6b2770a5d466cd2754802805",
+	}},
+	{noise.HandshakeXX, 0, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd16625443dbdf007bb4875a4d95a73282156c90715757356ed75e725b2b",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d4846641342d97be7d620d656cff580ca58e8f11cc5001702990645cd09164242e8540661edb60a6a8540e6c5e82f0bd95ab8b80c6f834d01a5e023cf46b51e3b2c9796244f4791c4f4c2ad9f7",
+		"2e8196d00b701c2f09f141b1c4ccc47e9dca2922bd2491c34cd76b8c3d337e08d7d5063ef629525d81a06e1ad0e8ce06a5a1e299a65623fcf08b6be25d3c554898997726450ccbb56652",
+		"7d2b55d4b79d038d8398d42d75bbf870e77ab1d73ba429e188da7010317c89",
+		"c6c2a7901abf013a515ab87928fb7e7d87207ae956668e0e9795fffc08b25c",
+	}},
+	{noise.HandshakeXX, 0, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662542dfece4dae76dc5ac85c1825d578f5381e8a6ca0ff8b782f9c86",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466807dca8877a33959186ac0181ac963a7dd13a134ae4a2191da2bedd96911f7b103f1588855356691eb8e399bd3cfd5f486ae9e443b823f0d58ec04fb2ea6275da3194a478e2d24af9a23",
+		"099fd29f5cad05f9d2c9be076d04e80a092e181fa13a4e844386e360defdca4e8223254ae6ec8e2f94404c1b2cfbf633637ea8dea2acb6fd2e6cf013d5bcd43885c658750a9d5af5c7ec",
+		"99521768e8b75eae6d56db072601817fd0606206ca444b02f911562521e4fd",
+		"ab15274dff9f222379cd9feac0e4a82b7fb61fc0c79372dd9c07d283b1e765",
+	}},
+	{noise.HandshakeXX, 1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662543c0d303a380071b3f64db0a10b87a34e6e9a7dd07f38de42e2db",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484660f7b3af6bee5a32d2cbc5fc98e2a038b06f0d7bd8f729fa5fc96261bd82d8e20f35ffcf131b4144444f8f4c1f2620cfaf327de53771ebf95f72dc1e5f96e3b88065ebc617fd26402c9b9",
+		"329e3f756fa718b350bc245530e8eaf1656bd2453e27eac247c6ef17a9786c276d69f49b2c4489657ca8d3bafa6d847b8aca4353c7b301862fe306a37fb7e182b5a52adbb6b9bb5e75f8",
+		"7897e21dd79636f43041a385c40e411e9545fafcc7460390a0f5bca87f43d2",
+		"c10882fc0a0c8abab4d609fabccaef31ab4a243b98bd69e8da2f4f4198d12a",
+	}},
+	{noise.HandshakeXX, 1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd16625409f815b8eb0dbb46e73ff10061ce4f668e7ad525d48d24612e49",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484667f0f30704cd806d42849595f4e39d8ace7b1f7ab9c62c9ccaf7284b3d8ce0d887fbc3e2f5ffd90a03e00af190dfee90ad6a978c9e0cba95a40c5af61409fd89acea9efe61cc3c5626453",
+		"3709db3d2b87c711bdd3ef87e62edd8a2775482a4421a58fb5eeb106861e98d2840ca8244e6063975c878004aa7ee991bc587962ef21463d1a0615316bea7af1a6b102acfd9bc4e8b07e",
+		"61eaa2290029bcde241e90efb965beeb7837ec5441928800275670fdb058de",
+		"ee55ef942191e45cf5bcea014c4a0c71f0780ff6095ff93f467e7a746e264c",
+	}},
+	{noise.HandshakeXX, 1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254909b4be38128fa1ee382b46bfbe2630124202d4749ea2d06487d",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484667c40d55b0bfa624fa6b23f02030ffaefe7925a9bd87194f8c4fe9d930dc959ec216aa6b7d41596b4f9e4905f412771befae5baae64f2b1cb55fbae113857fc75634d33125ef64c8515c5",
+		"249a8e4d794c3c6df1b51eda2254f2653b19d99d124278a759abe483bb6d7906375331eb14a43a9eed6ab1c848203e14e2e7127ee75d79d77d23068fe13e799ae21be2a6521f159608b9",
+		"f5a6516b19d933d6c0a5dc8c0145ce39e9dfac207ff6fa5c654d49bc6b1ab0",
+		"73464d1a429f4c20cbeb5d668e17cb5ec4158eea21db21bd2432f8b4733813",
+	}},
+	{noise.HandshakeXX, 1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662548b1c5b18c538c8c63ca5dd70a54c15168915bf5edbab2df12bf2",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484667017a62eaf03e7998188e6751f9fcac8bc79848fad62102c1936a2af6aef691c29dfd353ee7b2c1ac5031544aa7e2814f8fbe4180e999511b4e32e3fa0a009cdc7b9c20ce6b5787f9fcf",
+		"8f83154157fddfe88c38ce42a13b74127986fd61dc310450deb5ff6a181fa057d96927eaac505ff481efa6f1c092dd1880a72833458eaac993ba5e19641750e4f2195fa3af7b5f369a1a",
+		"776c311a7a1a1a31a0fa9950b6435a8116fc986c30aefb84b1de1a1ea3e0cf",
+		"c79820d7cd308ba3da226008598c022aeb8f084dde84fee307293a3634e331",
+	}},
+	{noise.HandshakeXX, 2, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd16625428c6a800c7db6b39e3456d482f8c37e883c3ff240c77407256ac",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466c1668235fca77e57f6b45e06441594cc4406092be47e85a4d0f04f5dd32d98f3093e9ada779e2aa06db725fb3f6bb18d7df7727229d5eb23876d530f89eb23a25a8e657c790f0c6a3128",
+		"30aea91ba89fda848b53828e1f7945fea8d996a165f077a5500c8418765d11c574f126a002bc70955d52879ea92abc5cf3f1ef96eb7fe2c084eebb273c797e0f31af83f23e200defd60c",
+		"1e7459e1dc3d1df643ad73799ce1d5594ca16f7cab5e7740b0b140828fd5be",
+		"b394b3d1392c8477446a5f29188f626d8c994794ed94abe3fc552b5aa0ad11",
+	}},
+	{noise.HandshakeXX, 2, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662544968e874077ca381c1f0127df29420b859cf36b5383c2d8d986b",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466c9996f0e38eded0281a0f505a4f2473b114924724e374c408b3ba103abc7ffbf8bc919f41c651555a9d4d5809975e676f0e6d093aa11303cacf701682c1dadd3666c1e965a3158618a30",
+		"4744625f46dfce9240a5b1927393fd862a2520366f4df66de4b75019d201de92891230ebf50cc2d52029ad960d7fa613d24b1336d8119acacd5745ce1ba24faa91d6cf7be217ff6799ad",
+		"70847317d915af289e3ff17e5d66e4b4b0020d1bd997b8bb17cfa15710db0b",
+		"ad071b14d700e2789c1251f57e3b1e455e3f3be012d7ab6abce986b536ba21",
+	}},
+	{noise.HandshakeXX, 2, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662543d46c611dff6b8421ca09dc79567842ed38928256a9a35128c20",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484662906b946c77f56bfe59790f6704d3506d892dec0ba85771b18f8d371e23e2970c8bc8b2a9fcbe96bcaf5dbb53d958841cfec6d91b61f265adeb8b93fde4f1c6d12a5a66fbd65265c67af",
+		"72cc9fc9a204b27abb198bdf41e73002d1b348de18ca66cd2574193a06fd9c64aa0e01924994fa145ddb10417d16aa53ed71162b3d3104ca4aa578762e51724a97e08b1762d715c7f9c0",
+		"1dcab75cb31e3e6d0e761090558ef90578435d3543a53ff7abca20ee152a82",
+		"86e26d4813675ea40de3cbbf14a4b80e6299422374ef84a3ee6d45b8f0e7b8",
+	}},
+	{noise.HandshakeXX, 2, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd16625444c72fdfcc26b692b243fd1e0d69cd5735b9af404ae7ddc7f15e",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466bc9f39c99cf603e2db23dbe9ce2adec44a572047d4a1d3f8c0df894e11f5bd7f9ed5a62a0d63ccfc5b60d1420eb1d4ac6d93f50fbd193f60379e2140cdb6860d1460b007be5c8064da95",
+		"b58fc4f341feab3fe253ee6489ad653afe49fcc6eea1f18a892b1a8febc146ee309032b5b433c34b4982844c2cc0e449ae4d3c4c228a97bbbbef52a9e44f4a52de079ba231a6b68a45c9",
+		"fc4829fa5f449a9aca1577156e58691997a90a5a55b7aa6401257535595eb9",
+		"b069da251403dba1f073a2fea640f5a8cc91d1f012a01c1fa87435a8492030",
+	}},
+	{noise.HandshakeXX, 3, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662544fd0dcd87f6b5d78feddd20bcb8ab9ed16ac202410f730729c74",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466d6a3135623749084e7af54bdb3cbefc74483b5a11791e66803483ca71b7a1cb9415f46d643edb50ac242a475f8c3b60dfe7df0a60a03a8c732ae2747ed5de74ce5ba4eca1461b96283f4",
+		"27f05826a4958e7232360fc6f2d5742baa781214efa55d1adfbbe6526577bfeec049bd84112dd940b7032911a753f227c454a891d33df307ce814db7b657f732eb230e8da83fea82aa08",
+		"6964e5f2c89c4cc61086163641d1b0af9ecfb4c3596726e00ad65361db462e",
+		"cf239ff75b592d7dcf14cb9d91cc682b9f216c8b98871a9e53461f0cd027de",
+	}},
+	{noise.HandshakeXX, 3, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254653658a6a90feb6404ce2902887f0faf388ff019393d23fd4976",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d4846696a7a5454cc70bb4eec2a2f7c616c143564ff1ae149458f9e70afb3498be7a886ed5d694d493c5867cb2c232205e46bddde1bfec74551b1f083a86e220331181777ca16a1bad616dff5f",
+		"f5b6224ea13577089dc14b20ca8e90d0cedede4faff50348d4d0a0f941182ad7e65025d045c6ff1f63a8b63ffe90710e734c20e3dd6c03cf438a6ce9aa9775b05dd5d3b729a9ac78d811",
+		"5e80fec73b32f6ff466aa5addbc2b16e2cf062f09c36796ecb2efcc35cac99",
+		"df3c8983cb9f286df65e57d0010dc65eeca3bca44b6b240da8ebf92be581cd",
+	}},
+	{noise.HandshakeXX, 3, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254425bfa36e76a8838d5d9f71f26594bce0a57407a4b1760b814ee",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484668e7a60ef8fbfa562e6542a22ef5e4944d733237508bf157ed4492e233b461620be67b52b7bd6053e7767ac64fd91e8df53bc6adbd943656eceef1daadc583828bf9580dc9229e5f87205",
+		"16fa408d9a67b448377f6a27288d735f09088bd0493bbab602a0b3608b6481380c5cf5a0a5a5b91aa942222b90b489a8f7c3bb2fffecbdf00ca7642bb47f1258177d174797f4d9846494",
+		"c45aa274ed25812f1a749351bcc6f968d834f8e2bb7008119c58fbe7a9eedd",
+		"53fd1fc7eecbbf238f3b67111f718da5d85381a0de6009a46e5a415e5f75ac",
+	}},
+	{noise.HandshakeXX, 3, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662545d791aebd7b1ff3a73ba67c693699d548895df3e86b1204b11fe",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466bb1259f77345353d70dcef1e97d161dd9c3324e72b46203ebe87dcb40159eb6603c900a563c48b22719b49f31437cfe9b1bfa8057f6e8f62584a5a0257c9eede97ecbafd2890e6551923",
+		"a702c30239110afbb8afacb639f961e5c2574c3fe59ee6069c0f5f5414ea2493462db30239ac36a9b70292f81f30fb9d3e3be30d1cb36cf2cd66b2c4bb6a84a19a1a06ab7ba66b78b51d",
+		"187cadad4158250d0af49c2aea3bedc34aee2cc962336fbe649527ca78e48c",
+		"91de652b73884e25506003fe72969748b9092a4518be9c6e4911a52b60375f",
+	}},
+	{noise.HandshakeIK, -1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254c9f0dff42c86abe5677abe74f6c87301577dbc1f3ffb2213827ca694a057fdbbacac81d639bfae65c7827558f90acd277316fcb3b0687be852fd7e392456bb6cbe070c749f1bd7c55fc2",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484667f1d8bd2b9b659695f90e35beaf5a5f5f1e7c83aa3194a2430cd",
+		"595694f9be48f03790f699455c84578b31d14a7baedfd736d73c53f66a5657",
+		"621ae446b11fda3cf08e56102dac9324dee37a4e536cdc878e8b454d98bcf2",
+	}},
+	{noise.HandshakeIK, -1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662544f8445e5dc2467b1e32653192d05dee85c4781bf0dd8d33ceebb5905a7a069f0d6bc97dbce6f8f0ee33d49311a72d0f80337527f958f92050deee33c19777fa17306346367055751bb3f",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466cb4a35db52355821787bb67f33957e7809370c44d33538ad5a42",
+		"226ca869f2777611f37350a7ab446f650c0cfe2855b7f020ce658bcf100f2d",
+		"90d84d69cd44829283b05d684879b53b8d714e51619b601438a1ae67caacd9",
+	}},
+	{noise.HandshakeIK, -1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254bd4f4131b33d738f4a2a299ee097f618811345c8fa0eb3de9fe75154b23f79e25007dbe6b36cbdfdf4a9cce3f3658622718a16e03b74978aee0e485864a129d991809e531504fe89590e",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466bca07c8ea8d3db6803fadea87e1a26dd748e73277a458ef6379a",
+		"b57093f3d1261319399a1150d5a937f3ef1a27415d2f9581d3bc0143eedefe",
+		"fe045568d1f521838b2eb348e07f26cd10332485732fb821ff8841ccc3b9d1",
+	}},
+	{noise.HandshakeIK, -1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd16625419d6fab175300a577115c701c41ed681373f0432f81d3bf8676bd05216cd1919e61b75ccef0c0cf0b216fcdf371d0859e6d8177aa9777fe9b8435bb6f8202c3acd9051a9aee0a63e76f6",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d4846658a7bb8caac5097833909e90778571d34ce0e5b6ea4c3a76f102",
+		"80a75e75c8e8d2e9c2a6c7bc6e550c4997d6d2b45429a530821c4aa5d36f27",
+		"b8475410da62a98493d33a1e669f8f56dd8f61d449b53bd375299c3435424a",
+	}},
+	{noise.HandshakeIK, 0, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254df1f9b7f2814e2464394f35e13e46862cb009157dc0352d710c45376c6cd25444c70354abe531df791866bd75aa9b66d08eafc7023007a5f9bcb8ee5cc55c0598e868acab1c02d9940cc",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466768fb8842b9bf487b26ebb07b2a3d6a12d28f27f2e833b2bf498",
+		"58a23ba181962ac5c0fafdf3c45870d1d8ba060076a80550ced748c69e6cc7",
+		"ef66353b5543937ee497369867d9559a0166a817be50780a7677583341cccf",
+	}},
+	{noise.HandshakeIK, 0, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662542723c3e2684abdc13656c8196a8dbd704eae2b5029b57d6f1387e1cf81cf8d96ae04ae107f5c4eac43b568cb7ee3bb076cad606d45c38082aaf08ff5dab57e5a624f16bf3630c2c5f104",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466a3cacf615ab82f05d73a50c3841ebf6bba91d1631bb4a33552e6",
+		"fd57c203d5a1b8ae62c297614b5d71668c52ab4e70c1cbbc52c0538a069ccd",
+		"4cca33eb83505243974e576cbd818c98d3b346ff5c3374fac028ff153a4e9b",
+	}},
+	{noise.HandshakeIK, 0, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254c073edcce60db134899fe51feb700d92514276978d84cd27efe39f2691af2d4add525786f0b5486403c99532773cf71d67cea5eea88023df452c1a2188b56660ad8a4a43693fff90497a",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484660fce1dc5223bdd289d0d0b6dabc844dbacd0c7bc02535162cde6",
+		"323a0be447f4985589dea6ff8f3f4d27d810be1e96862868332a4ad903a060",
+		"109670cd7de301fe4d6c3621935bc2d935db08c60b76f7096015ebcb0f5679",
+	}},
+	{noise.HandshakeIK, 0, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254694ead724bb690ad27ce3893ebd8394b455e44e362122cce141b66200c2ac5a340048ce6c8456ff4837c29fe67256f8117106241219f60be8d1ad5cce3624dd12b08c8095b0abfe558a2",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466f6bef292d60d7dd4c6d103923a164717d1f2c43d4a0be832d29f",
+		"2471b2688160616fc0bd108fde1be5848e763d448a018f8f9052697444a95a",
+		"e48f8d2d66ccb8f59321228086764d403dac49de50617604bd4e1399ec7714",
+	}},
+	{noise.HandshakeIK, 1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662548aac9b7e52346a4de47756a681e5a97c720eef8b7faf4d312878c47eca4a0a01eb23f043b5a6a476be780e530597c256e47b72ebc70cede5e0b1d7616523787a70c730858ab01037164b",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466b5f98fed60b91df5d962e3be6a027433a7179f44c7a09ee68b93",
+		"01b3d709f2acd4b0d95de75506da47ad980c471f2afd3a436cdc59f5605a4a",
+		"d2d77f0af04d0086e77a59b4d5a4428115f60ef95ee23a56683e35a29dcb00",
+	}},
+	{noise.HandshakeIK, 1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254b4a9c4176c417784b1ee28a0f323750682da959b44f9d8e06a07f757567492fa875cb562717ab59a6cc44f6b90abbc69363eebbdb99964a60f81d1bdca6741998d3df66cc4c3f7a20991",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466e07ee913ea981e364239b86129146a0dcf47f65877606625369d",
+		"4492510a2b642757ad4089fda1333476635f5e8d984d8de917325a480380c8",
+		"1e263d17ead44ed55677c2a12b11a3c9b625d3aa9f128b279cd5e281d5a8d9",
+	}},
+	{noise.HandshakeIK, 1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254afb4846f0ab102f56af491cdd2855b8a556936a17ac486c0f6446da6cafb1971afe73d2065190e650c2770cc69910e9c9265090973e176e6ac4cf5cd4db3bf6527f987a742a2c83252d9",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466d6edc0252eaef96ad780ac004ef914e91c2a81181764334ca856",
+		"1cb7c2b91b71ce817e0958bed36b5644996d8f2c63e4667627792127def75e",
+		"56320be0ca19a8b310230dca8e09b0002288e86e297286ca5810e913102170",
+	}},
+	{noise.HandshakeIK, 1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254d090a76917ed86b1ca3f8af8ac5c0803d5b3b290ab95fa415d8bf2f9200a59fc0aef8b6d695b38b638d8a84ff6029bfa720b9cbc2e1f0e39ae53481de7823a9ec40e8e82d4e52bdbe833",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466c92aa230bccd4126f41bba00c0183e8a92b2d41d3874e2d39c67",
+		"245e2f9694b825a856dc97709fcc450870d23dd07637b57d21268ad60016e4",
+		"977eb8234bef8ece7a14c771fa5019aae42c0f4655d4e1ffbfdb4a96def193",
+	}},
+	{noise.HandshakeIK, 2, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254d06f15f78ad0914d9715147bb5a5004b27345a838bab4aa8bc5f144afc2cf4ccb88f9ea1ebd99e94b76e50af7eee0e596a3d77b86f9aa87dcfe61d972bc6f34d0e93751d1260fa6bf0fe",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466168e6913e78d7a2b04b2b154c5149032d1c2584051bdcf04db1d",
+		"6013ea114b4c4884afb82bf029f72f924bd8a32c487a15a1cef4855ba234be",
+		"8a2e7119635e41a35b7e64e0adac5483b66b1a9827895124ea07d58440b654",
+	}},
+	{noise.HandshakeIK, 2, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662545bdb2d5031ac09dcb167ccedf2898899c56e3e963e1e707a4df1bed7f3f9594b873a288972e606ec27a89d7805c31c4cd218fc428fc8457cdf1976bed363286c0e199a5df2a8dc631f33",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466b86d10d43f68d1d06674b4ceee887769c53e3b7a239e76287e1f",
+		"abbc8826715c00752948d22874560b57dc102dbf6c3dd853037efdd9499ad0",
+		"f80bad7ea7c64490f8123d2728a176c3afb97a59f197c7b1be246b7cd3eb1d",
+	}},
+	{noise.HandshakeIK, 2, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd16625496931a9f8e5df3b9bfb984905e85191f366b770652f2222b7fa15c23eb721eaf0e22fb53171332b0d6d9cbd79d3f1421498cac29da3dc9ae306c19babad8b3a5f6d7be72eca9272f7667",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466cf6f8562c58cda446114b33c5291da3398dc9b9485610b524536",
+		"4a5d72c8e755787497f85fc22c91244a26efe8edb79806caf15e0c128b5e01",
+		"f31be3c70e06897a35d0167821f144b438b6a3fda809f20fca377895727730",
+	}},
+	{noise.HandshakeIK, 2, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662540322be5210eec7e84567f5b4ad376b908b7c38a587eb71776e0661a6ca9f3ef2da7e079ebdd84739c3bce2764827999b2dbe7ee0a408573e5466b25ab358115f0cafc7c888119dfb98cc",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466574ad0a465f5fa106657b9f7927e737f39dbed9fe3bf511849f9",
+		"c033f4a3312af700a5a655f6992bcad095ceb5af11b02027cecd87ef65738c",
+		"3363987af8578ae96cb358858a859ef8060129a05d85700d8a9c4955c599c1",
+	}},
+	{noise.HandshakeKK, -1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd16625419aad3185cdd8cf655a139d31873c6117854954b8fd22d9e6489",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484668f22a41cfad6486600f0fbb4ae670da71c5206d2817405a505d7",
+		"674d3b19a02535626dfcb13bd383509b715566cf53e9f31ac161944d38b7c1",
+		"aefd829d861f03d0d8c4bfedbb520f7c1837a46e2e1b26896063783b1cba4b",
+	}},
+	{noise.HandshakeKK, -1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254558809aaeff03abdf354ad47d26523f1b98b5ce386c3b066ff53",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466f7c3b2f7cef28a2f212487967f4b709e22ff452dcb68821a10aa",
+		"ab44bf778165ad086eaebbb994df826628b3fe26ad310642480a1b2af8fc23",
+		"baacf816b83aaeb15954621113f8e0603cb79168fe6308b87413004beee4d2",
+	}},
+	{noise.HandshakeKK, -1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662548b607e12f23e87019e6eb13b92b5c3d17ba0183cb1389dcef1a6",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484667f550ee88c31bb77034f98d4889d5f34812eb534a2eca91ba158",
+		"31f8fb1587255c4b0dd700a9b6c8f43f8a784c2b182fd9c90e236708314b5d",
+		"e536a299bf11d6abe9d3a94183d029f1eb3e12e11b3dab41e21789869dcc93",
+	}},
+	{noise.HandshakeKK, -1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254f076403f2e0cdd201c5a743d4aab448e6e3b29d4aa05628a5cbd",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466f12abbcda56565bf3fa37b196488daf515b7434096aa1638346b",
+		"0e79035855cdea04bc833d5ff63291042c6e12b0ac55ef2c4096deed1cbac2",
+		"c6dbcc2ac8f85338732b71a58f4c3be89bdfa7b2da8a8506ec4f1d2d9299a0",
+	}},
+	{noise.HandshakeKK, 0, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254c98a7a53fef3f35c4755296b675ec07df2f17c802c6264ef8d4a",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484666d587d79056823bbadec72c5647701d2468995235e0e2dfa6130",
+		"42e238b4b6180654120bdb79734c6bbf8d47cc49f94689740b89443a5f809c",
+		"556615110f73692755058a5f80d58f952a3eecccd14006edf6dcff48f44b5a",
+	}},
+	{noise.HandshakeKK, 0, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254b999c716e2b5ebd7dddc071a74c7c4c9cb80ac268b23a5284fc2",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466ad07cf9d7d4c88039123b962375716b252dc4ad12cfe6f5d4bb8",
+		"fe6f6f749a1495883a1715674543c2ebe56c1cb674d4f4e47f9086553700ce",
+		"7a88c03c458db5b49827d899535210ba453d7dfc0f76a661b8cad08cf967ef",
+	}},
+	{noise.HandshakeKK, 0, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254aa72dba4eb5155d2465a99e91f15ca6c1ffa47e49328ada51b0e",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d4846662d579964a47442efacb52b05a270d3e7875aa8266a03076750c",
+		"91e44c0d5d9cd28abf7c8b30bb2e3d0550239b9e17953d9f089ec3d930df27",
+		"2e741b2768bfd0bbfeda04cbd22add5afa67b677a3b01af28dbaf63159b9bc",
+	}},
+	{noise.HandshakeKK, 0, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd16625413c6b4c495bbc7b95432535cc6716834442ddae5e177d5bfd397",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466169cfd28ccd38213e17b518f78c70703c52b0d8d51d4479b557a",
+		"13f09ec7877b005731a876958cec004a7f9c2734e971828082db441ce001c1",
+		"cf3a9cb9da7eb9bea19447b1f9bea60ac70124dfef886e9b82cd52f748c682",
+	}},
+	{noise.HandshakeKK, 1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662543deac12a288a3ffb25418beeca17308cd9c7c9931d1ab774f12f",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d4846639f393a7a74357488e1f1ba5d4c24b572488251c32b03de1ab63",
+		"19e26089ed5d9ec2cd5a9488c012880c76670e7b6ef405e518a658008f829c",
+		"32a0cd0ddbbe90796cde8048bc59f2c3544948b03e6675e4ae0437baf0df06",
+	}},
+	{noise.HandshakeKK, 1, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662541a96b079a0d93c9c8a154abc656a5b149ecbeb06e87583b65868",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466677a31f9e25c33229514bb8c7076d4faff8789a0e351738b86fd",
+		"de7af56efebaae1c6a616b624b39a441d8b82e09a2f2a30531a7c30441e6eb",
+		"408aae72aaa26f98d436f4a64c34ea26baa1802548774b8f14cff3d1891895",
+	}},
+	{noise.HandshakeKK, 1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662549e185b6bf0ab965775140f26a11fb5f259000251c39c78189511",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466678170eb78b10bba2423c39edf7b3642c622754f7e6de1afc949",
+		"d95cc1c999c97bbeedd5c7ec4652084d4b62a16f82500d755aa618eb4a53e5",
+		"868d5fcee2fc24e0449b05781857718f8a52fa5c7e3e91e31835579480126c",
+	}},
+	{noise.HandshakeKK, 1, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254f45eb20acd443feff46b315ad0366d803b7da09e6b84e12c1064",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466d510ee9dd1b542ca5a0dca75e69bc396eea2dadec726382e2030",
+		"96252868cb85131fc634b43f37c135da2b02902158369ec7a8e6b45b246732",
+		"52822ad96c98c7ebcf68cafe91b6f18929aad75af5424a973cdb0f004b3832",
+	}},
+	{noise.HandshakeKK, 2, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254935aac7f2c79135a1ae93f52e8f9b4b46fb2393b2bdacb48565c",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466b1b80dee04d5354522d85eed0973dca2d7cee4415256eac4724d",
+		"a0136e8d63fc19792c4a64e752e7de2ee2b61eb0ae0298595363785c8f653d",
+		"fbb11e69ee5a33728ed2823c05a6fa19dc7d73d6af4467e990e87941bd582f",
+	}},
+	{noise.HandshakeKK, 2, noise.CipherSuite{Cipher: noise.ChaChaPoly, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662543d75d4599f7a207000b9c74a6e5fb64546dabaea326eae41e103",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d484669ba2c1c12e0ae525b54442c957df906bb8905ee7620374ebf0b2",
+		"cdafd1b6afea01667934ed26de85e3a200927343f534ccee0bdab0fbbe5aee",
+		"2c4b0bdb11291ee7b67616af3c37e7a9f8c400c715e7f9142a620db4fd3e05",
+	}},
+	{noise.HandshakeKK, 2, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.BLAKE2s}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254669c71c550b8a43b34354d21b89675f5c3ab513ce0b34156462e",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466728e2a0d6eeceaee629a7e4b94070395666bc89812f54a5e443b",
+		"fe234b7f281411b7c6b3d7b75466259f4e5f05c12ba78c04f44432f0fba361",
+		"9e35422ab4388dd10073b1a0ef26ad00041372fdf968809e67f56f1b6ef723",
+	}},
+	{noise.HandshakeKK, 2, noise.CipherSuite{Cipher: noise.AESGCM, Hash: noise.SHA256}, []string{
+		"358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662540590c32754712b9ce391bf35caa325f1ed107ec12d5cb4fc49af",
+		"64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466f1bd851e93bfded9a6c776e0614d573731a40e7f562f6f3c067f",
+		"d219ab65f4416d18e67f501afa99f43009c0a1c2c78427b1dcbdd6cf020928",
+		"1c6b3e31392287e06fcc5a885ea8690e39a2d3d54e23f5aeca14fca3c2f053",
+	}},
+}
+
+const noisePSK = "2176657279736563726574766572797365637265747665727973656372657421"
+
+// Returns the payload of message i of a handshake with n messages:
+// "test_msg_i", then "yellowsubmarine" and "submarineyellow".
+func noisePayload(i int, n int) []uint8 {
+	if i < n {
+		return []uint8("test_msg_" + strconv.Itoa(i))
+	}
+	if (i-n)%2 == 0 {
+		return []uint8("yellowsubmarine")
+	}
+	return []uint8("submarineyellow")
+}
+
+func noiseKey(s string) *noise.DHKey {
+	var priv axlsign.PrivateKey
+	copy(priv[:], unhex(s))
+	var key = noise.NewDHKey(priv)
+	return &key
+}
+
+func noiseTest() bool {
+	var initStatic = noiseKey("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	var respStatic = noiseKey("0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20")
+
+	for _, v := range noiseVectors {
+		var ci = noise.Config{CipherSuite: v.suite, Pattern: v.pattern, Initiator: true,
+			Prologue: []uint8("notsecret"), StaticKeypair: initStatic,
+			EphemeralKeypair: noiseKey("202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f")}
+		var cr = noise.Config{CipherSuite: v.suite, Pattern: v.pattern,
+			Prologue: []uint8("notsecret"), StaticKeypair: respStatic,
+			EphemeralKeypair: noiseKey("4142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f60")}
+		if len(v.pattern.ResponderPreMessages) > 0 {
+			ci.PeerStatic = &respStatic.Public
+		}
+		if len(v.pattern.InitiatorPreMessages) > 0 {
+			cr.PeerStatic = &initStatic.Public
+		}
+		if v.psk >= 0 {
+			ci.PresharedKey, ci.PresharedKeyPlacement = unhex(noisePSK), v.psk
+			cr.PresharedKey, cr.PresharedKeyPlacement = unhex(noisePSK), v.psk
+		}
+
+		// c1 encrypts from the initiator to the responder, c2 the other way.
+		var hsI, _ = noise.NewHandshakeState(ci)
+		var hsR, _ = noise.NewHandshakeState(cr)
+		var i1, i2, r1, r2 *noise.CipherState
+		var n = len(v.pattern.Messages)
+		for i := 0; i < n; i++ {
+			var writer, reader = hsI, hsR
+			if i%2 != 0 {
+				writer, reader = hsR, hsI
+			}
+			var payload = noisePayload(i, n)
+			var msg, w1, w2, err = writer.WriteMessage(nil, payload)
+			if err != nil || hex.EncodeToString(msg) != v.msgs[i] {
+				return false
+			}
+			var res, d1, d2, rerr = reader.ReadMessage(nil, msg)
+			if rerr != nil || !bytes.Equal(res, payload) {
+				return false
+			}
+			i1, i2, r1, r2 = w1, w2, d1, d2
+			if i%2 != 0 {
+				i1, i2, r1, r2 = d1, d2, w1, w2
+			}
+		}
+
+		for i := n; i < len(v.msgs); i++ {
+			var enc, dec = i1, r1
+			if (i-n)%2 != 0 {
+				enc, dec = r2, i2
+			}
+			var payload = noisePayload(i, n)
+			var ct, _ = enc.Encrypt(nil, nil, payload)
+			var pt, err = dec.Decrypt(nil, nil, ct)
+			if hex.EncodeToString(ct) != v.msgs[i] || err != nil || !bytes.Equal(pt, payload) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package main
+
+import "encoding/hex"
+import "curve25519-go/axlsign"
+
+// Small-order public key and R values from the ZIP 215 test cases, valid
+// for the message "Zcash" only under the ZIP215 policy. The last one has a
+// non-canonical R (y = p).
+var zip215Vectors = []struct {
+	pub, sig string
+}{
+	{"0100000000000000000000000000000000000000000000000000000000000000",
+		"01000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"},
+	{"0100000000000000000000000000000000000000000000000000000000000000",
+		"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a0000000000000000000000000000000000000000000000000000000000000000"},
+	{"0100000000000000000000000000000000000000000000000000000000000000",
+		"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f0000000000000000000000000000000000000000000000000000000000000000"},
+}
+
+// Checks the policies against the ZIP 215 cases, S on both sides of L
+// and a signature made malleable by adding L to S.
+func policyTest(priv axlsign.PrivateKey, msg []uint8) bool {
+	var strict = axlsign.VerifyOptions{Policy: axlsign.Strict}
+	var cofactored = axlsign.VerifyOptions{Policy: axlsign.Cofactored}
+	var zip215 = axlsign.VerifyOptions{Policy: axlsign.ZIP215}
+
+	for _, v := range zip215Vectors {
+		var pub axlsign.Ed25519PublicKey
+		var sig axlsign.Signature
+		var b, _ = hex.DecodeString(v.pub)
+		copy(pub[:], b)
+		b, _ = hex.DecodeString(v.sig)
+		copy(sig[:], b)
+
+		var msg = []uint8("Zcash")
+		if !axlsign.VerifyEd25519WithOptions(pub, msg, sig, zip215) ||
+			axlsign.VerifyEd25519WithOptions(pub, msg, sig, strict) ||
+			axlsign.VerifyEd25519WithOptions(pub, msg, sig, cofactored) {
+			return false
+		}
+	}
+
+	// S up to L - 1 with the identity key: only ZIP215 allows the key, and
+	// no policy accepts S from L on.
+	var smsg = []uint8("S")
+	for _, v := range ed25519ScalarVectors {
+		var pub, sig = scalarSignature(v.r, v.s)
+		if axlsign.VerifyEd25519WithOptions(pub, smsg, sig, zip215) != v.ok ||
+			axlsign.VerifyEd25519WithOptions(pub, smsg, sig, strict) ||
+			axlsign.VerifyEd25519WithOptions(pub, smsg, sig, cofactored) {
+			return false
+		}
+	}
+
+	// S + L: accepted by Verify, rejected by every policy.
+	var L = []uint8{0xed, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58,
+		0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10}
+	var pub = priv.Public()
+	var sig = priv.Sign(msg)
+	var signBit = sig[63] & 128
+	sig[63] = sig[63] & 127
+	var c = 0
+	for i := 0; i < 32; i++ {
+		c += int(sig[32+i]) + int(L[i])
+		sig[32+i] = uint8(c)
+		c = c >> 8
+	}
+	sig[63] = sig[63] | signBit
+
+	return pub.Verify(msg, sig) &&
+		!axlsign.VerifyWithOptions(pub, msg, sig, strict) &&
+		!axlsign.VerifyWithOptions(pub, msg, sig, cofactored) &&
+		!axlsign.VerifyWithOptions(pub, msg, sig, zip215)
+}
@@ -0,0 +1,83 @@
+package main
+
+import "bytes"
+import cryptorand "crypto/rand"
+import "curve25519-go/axlsign"
+import "curve25519-go/ratchet"
+
+// Double Ratchet conversation between alice and bob, with messages out of
+// order, a tampered message and a session restored from its serialization.
+func ratchetTest(headerEncryption bool) bool {
+	var sk, hka, nhkb [32]uint8
+	cryptorand.Read(sk[:])
+	cryptorand.Read(hka[:])
+	cryptorand.Read(nhkb[:])
+	var bobPub, bobPriv, _ = axlsign.GenerateKeyDefault()
+	var ad = []uint8("AD")
+	var cfg = ratchet.Config{MaxSkip: 10}
+
+	var alice, bob *ratchet.Session
+	if headerEncryption {
+		alice, _ = ratchet.NewInitiatorHE(sk, bobPub, hka, nhkb, cfg)
+		bob = ratchet.NewResponderHE(sk, bobPriv, hka, nhkb, cfg)
+	} else {
+		alice, _ = ratchet.NewInitiator(sk, bobPub, cfg)
+		bob = ratchet.NewResponder(sk, bobPriv, cfg)
+	}
+
+	if _, err := bob.Encrypt([]uint8("hola"), ad); err != ratchet.ErrNoSendingChain {
+		return false
+	}
+
+	var send = func(from *ratchet.Session, n int) ([]ratchet.Message, [][]uint8) {
+		var msgs []ratchet.Message
+		var pts [][]uint8
+		for i := 0; i < n; i++ {
+			var pt = []uint8{uint8(i), 'm', 's', 'g'}
+			var m, _ = from.Encrypt(pt, ad)
+			msgs = append(msgs, m)
+			pts = append(pts, pt)
+		}
+		return msgs, pts
+	}
+	var recv = func(to *ratchet.Session, m ratchet.Message, pt []uint8) bool {
+		var got, err = to.Decrypt(m, ad)
+		return err == nil && bytes.Equal(got, pt)
+	}
+
+	// desordenados
+	var msgs, pts = send(alice, 3)
+	if !recv(bob, msgs[2], pts[2]) || !recv(bob, msgs[0], pts[0]) {
+		return false
+	}
+
+	// alterado: no cambia el estado
+	var bad = ratchet.Message{Header: msgs[1].Header, Ciphertext: append([]uint8{}, msgs[1].Ciphertext...)}
+	bad.Ciphertext[0] ^= 1
+	if _, err := bob.Decrypt(bad, ad); err != ratchet.ErrDecrypt {
+		return false
+	}
+
+	// guardado y restaurado
+	var state, _ = bob.MarshalBinary()
+	var restored, err = ratchet.Restore(state, cfg)
+	if err != nil {
+		return false
+	}
+	bob = restored
+	var late, latePt = msgs[1], pts[1]
+
+	var replies, rpts = send(bob, 2)
+	if !recv(alice, replies[1], rpts[1]) || !recv(alice, replies[0], rpts[0]) {
+		return false
+	}
+	msgs, pts = send(alice, 1)
+	if !recv(bob, msgs[0], pts[0]) || !recv(bob, late, latePt) {
+		return false
+	}
+
+	// demasiados mensajes saltados
+	msgs, _ = send(alice, 12)
+	var _, skipErr = bob.Decrypt(msgs[11], ad)
+	return skipErr == ratchet.ErrTooManySkipped
+}
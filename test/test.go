@@ -1,23 +1,60 @@
 package main
 
 import "fmt"
-import "math/rand"
+import "crypto"
+import "errors"
+import cryptorand "crypto/rand"
 import "curve25519-go/axlsign"
 import b64 "encoding/base64"
-import "time"
+import "bytes"
+import "io"
+import "os"
+import "strings"
+import "sync"
+import "sync/atomic"
 
 func randomBytes(size int) []uint8 {
-	rand.Seed(time.Now().UTC().UnixNano())
-	var High int = 255
 	var seed = make([]uint8, size ) 
-	for i := 0; i < len(seed); i++ { 
-		seed[i] = uint8(rand.Int() % (High + 1))
+	if _, err := cryptorand.Read(seed); err != nil {
+		panic(err)
 	}
 	return seed
 }
 
+// Verifies the same signed message buffer repeatedly and from several
+// goroutines at once; the buffer must not change and every check must pass.
+func sharedBuffer(publicKey []uint8, msg []uint8, signedMsg []uint8) bool {
+	var orig = append([]uint8(nil), signedMsg...)
+	var fails int32
+
+	for i := 0; i < 3; i++ {
+		if axlsign.OpenMessage(publicKey, signedMsg) == nil {
+			fails++
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if axlsign.OpenMessage(publicKey, signedMsg) == nil || axlsign.Verify(publicKey, msg, signedMsg[:64]) != 1 {
+				atomic.AddInt32(&fails, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return fails == 0 && bytes.Equal(orig, signedMsg)
+}
+
 func main() {
-    fmt.Println("\nHello, curve25519\n")
+	if len(os.Args) > 1 && os.Args[1] == "bench" {
+		benchmarks()
+		return
+	}
+
+    fmt.Print("\nHello, curve25519\n\n")
      	
 	// random seed
 	var seed = randomBytes(32)
@@ -41,7 +78,116 @@ func main() {
 	fmt.Printf("Res: %d\nRes2: %d\n", res, res1)
 	fmt.Println(texto)
 	fmt.Println(smsg)
+
+	// errores
+	
+	var _, errKey = axlsign.SignE(keys.PrivateKey[:16], msg, rnd)
+	var errLen = axlsign.VerifyE(keys.PublicKey, msg, sig[:32])
+	var errSig = axlsign.VerifyE(keys.PrivateKey, msg, sig)
+	var _, errShort = axlsign.OpenMessageE(keys.PublicKey, sigmsg[:63])
+	fmt.Printf("ErrKey: %v\nErrLen: %v\nErrSig: %v\n", errKey, errLen, errSig)
+	fmt.Printf("ErrShort: %v\n", errShort == axlsign.ErrInvalidSignatureSize && errLen == axlsign.ErrInvalidSignatureSize && errSig == axlsign.ErrInvalidSignature)
 		
+	// claves tipadas
+
+	var priv axlsign.PrivateKey
+	copy(priv[:], keys.PrivateKey)
+	var pub = priv.Public()
+	var tsig = priv.Sign(msg)
+	fmt.Printf("Typed: %v %v\n", pub.Verify(msg, tsig), axlsign.PublicKey(priv).Verify(msg, tsig))
+
+	var gpub, gpriv, _ = axlsign.GenerateKeyDefault()
+	var rsig, _ = axlsign.SignRandom(gpriv, msg)
+	fmt.Printf("Random: %v\n", gpub.Verify(msg, rsig))
+
+	var bpubs = [][32]uint8{ pub, gpub, pub }
+	var bmsgs = [][]uint8{ msg, msg, []uint8( texto + "!" ) }
+	var bsigs = [][64]uint8{ tsig, rsig, tsig }
+	var ballOK, bperItem = axlsign.VerifyBatch(bpubs, bmsgs, bsigs)
+	fmt.Printf("Batch: %v %v\n", ballOK, bperItem)
+
+	// Clave de orden 2 (u = 0), R = identidad y S = 0: Verify acepta
+	// cuando h es par, la política Cofactored y VerifyBatch la rechazan.
+	var lpub axlsign.PublicKey
+	var lsig axlsign.Signature
+	lsig[0] = 1
+	var lmsg []uint8
+	for i := 0; axlsign.Verify(lpub[:], lmsg, lsig[:]) != 1; i++ {
+		lmsg = []uint8{uint8(i)}
+	}
+	var _, lperItem = axlsign.VerifyBatch([][32]uint8{lpub, pub}, [][]uint8{lmsg, msg}, [][64]uint8{lsig, tsig})
+	var lcof = axlsign.VerifyWithOptions(lpub, lmsg, lsig, axlsign.VerifyOptions{Policy: axlsign.Cofactored})
+	fmt.Printf("BatchPolicy: %v %v\n", lperItem, lcof)
+
+	// La misma clave con R = -B y S = L - 1, por encima de 2^252: lote y
+	// firma individual deben coincidir.
+	var hsig axlsign.Signature
+	copy(hsig[:], unhex("58666666666666666666666666666666666666666666666666666666666666e6ecd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010"))
+	var hmsg []uint8
+	for i := 0; axlsign.Verify(lpub[:], hmsg, hsig[:]) != 1; i++ {
+		hmsg = []uint8{uint8(i)}
+	}
+	var hallOK, hperItem = axlsign.VerifyBatch([][32]uint8{pub, lpub, gpub}, [][]uint8{msg, hmsg, msg}, [][64]uint8{tsig, hsig, rsig})
+	var hcof = axlsign.VerifyWithOptions(lpub, hmsg, hsig, axlsign.VerifyOptions{Policy: axlsign.Cofactored})
+	fmt.Printf("BatchScalar: %v %v\n", !hallOK && hperItem[0] && hperItem[1] == hcof && hperItem[2], hcof)
+
+	var ssigner = axlsign.NewSigner(priv)
+	io.Copy(ssigner, strings.NewReader(texto))
+	var ssig = ssigner.Sign()
+	var sverifier = axlsign.NewVerifier(pub, ssig)
+	io.WriteString(sverifier, texto)
+	fmt.Printf("Stream: %v %v\n", sverifier.Verify(), pub.Verify(msg, ssig))
+
+	var xsig, _ = axlsign.SignXEdDSA(cryptorand.Reader, priv, msg)
+	fmt.Printf("XEdDSA: %v %v\n", axlsign.VerifyXEdDSA(pub, msg, xsig), axlsign.VerifyXEdDSA(gpub, msg, xsig))
+	fmt.Printf("XEdDSA vectors: %v\n", xeddsaTest())
+
+	fmt.Printf("VRF: %v\n", vrfTest(priv, gpub, msg))
+
+	fmt.Printf("Ed25519 RFC 8032: %v %v\n", ed25519Test(), ed25519ScalarTest())
+
+	fmt.Printf("Policies: %v\n", policyTest(priv, msg))
+
+	var lowOrder = make([]uint8, 32)
+	lowOrder[0] = 1
+	var ecdh1, _ = axlsign.ECDH(priv[:], gpub[:])
+	var ecdh2, _ = axlsign.ECDH(gpriv[:], pub[:])
+	var _, errLow = axlsign.ECDH(priv[:], lowOrder)
+	fmt.Printf("ECDH: %v %v\n", bytes.Equal(ecdh1, ecdh2), errLow)
+
+	var kinfo = []uint8("curve25519-go session")
+	var dkey1, _ = axlsign.DeriveKey(priv[:], gpub[:], kinfo, 64)
+	var dkey2, _ = axlsign.DeriveKeyWithOptions(gpriv[:], pub[:], kinfo, 64, axlsign.DeriveKeyOptions{})
+	var hpub = gpub
+	hpub[31] = hpub[31] | 128
+	var dkey3, _ = axlsign.DeriveKey(priv[:], hpub[:], kinfo, 64)
+	fmt.Printf("DeriveKey: %v %v\n", len(dkey1) == 64 && bytes.Equal(dkey1, dkey2), bytes.Equal(dkey1, dkey3))
+
+	var nonce [24]uint8
+	cryptorand.Read(nonce[:])
+	var sealed = axlsign.Seal(nil, msg, &nonce, gpub, priv)
+	var opened, bok = axlsign.Open(nil, sealed, &nonce, pub, gpriv)
+	sealed[0] = sealed[0] ^ 1
+	var _, bok2 = axlsign.Open(nil, sealed, &nonce, pub, gpriv)
+	fmt.Printf("Box: %v %v\n", bok && bytes.Equal(opened, msg), bok2)
+
+	var sealedAnon, _ = axlsign.SealAnonymous(keys.PublicKey, msg)
+	var openedAnon, aerr = axlsign.OpenAnonymous(keys, sealedAnon)
+	fmt.Printf("Sealed box: %v\n", aerr == nil && bytes.Equal(openedAnon, msg))
+
+	fmt.Printf("HPKE RFC 9180: %v %v\n", hpkeTest(), hpkeAuthTest(priv, gpub, gpriv, msg))
+	fmt.Printf("X3DH: %v\n", x3dhTest(priv, gpriv))
+	fmt.Printf("Double Ratchet: %v %v\n", ratchetTest(false), ratchetTest(true))
+	fmt.Printf("Noise: %v\n", noiseTest())
+	fmt.Printf("Ristretto255 RFC 9496: %v\n", ristrettoTest())
+	fmt.Printf("Elligator 2: %v %v\n", elligatorTest(priv), elligatorVectorsTest())
+	fmt.Printf("Hash to curve RFC 9380: %v\n", hashToCurveTest())
+
+	var signer crypto.Signer = axlsign.SigningKey(priv)
+	var csig, _ = signer.Sign(cryptorand.Reader, msg, crypto.Hash(0))
+	var _, herr = signer.Sign(cryptorand.Reader, msg, crypto.SHA512)
+	fmt.Printf("Signer: %v %v\n", axlsign.Verify(keys.PublicKey, msg, csig) == 1 && pub.Equal(signer.Public()), errors.Is(herr, axlsign.ErrHashedMessage))
+
 	// Control 
 	
 	var b64sk, _ = b64.StdEncoding.DecodeString( "QEK6Xm/ourxQVlBzaOdVxYBeew8dlQ7dYrqEI60ksmo=" )
@@ -69,5 +215,6 @@ func main() {
 	fmt.Println("sig: " + b64.StdEncoding.EncodeToString(sig) )		
 	fmt.Println("sig+msg: " + b64.StdEncoding.EncodeToString(sigmsg) )		
 	fmt.Println("msg: " + smsg )
+	fmt.Printf("Shared buffer: %v\n", sharedBuffer(keys.PublicKey, msg, sigmsg))
 	
 }
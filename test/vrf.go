@@ -0,0 +1,64 @@
+package main
+
+import "bytes"
+import cryptorand "crypto/rand"
+import "curve25519-go/axlsign"
+
+// Adds L to the scalar s of a proof, which keeps it valid modulo L.
+func vrfAddL(proof axlsign.VRFProof) axlsign.VRFProof {
+	var L = []uint8{0xed, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58,
+		0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10}
+	var c = 0
+	for i := 0; i < 32; i++ {
+		c += int(proof[64+i]) + int(L[i])
+		proof[64+i] = uint8(c)
+		c = c >> 8
+	}
+	return proof
+}
+
+// Checks that proofs with different Z give the same output, that the
+// same Z gives the same proof, and that wrong keys, messages and
+// tampered or non-canonical proofs are rejected.
+func vrfTest(priv axlsign.PrivateKey, other axlsign.PublicKey, msg []uint8) bool {
+	var pub = priv.Public()
+	var z = make([]uint8, 64)
+
+	var proof, out, err = axlsign.VRFProve(cryptorand.Reader, priv, msg)
+	var zproof, zout, zerr = axlsign.VRFProve(bytes.NewReader(z), priv, msg)
+	var zproof2, _, _ = axlsign.VRFProve(bytes.NewReader(z), priv, msg)
+	if err != nil || zerr != nil || out != zout || proof == zproof || zproof != zproof2 {
+		return false
+	}
+	if _, _, err = axlsign.VRFProve(bytes.NewReader(z[:63]), priv, msg); err == nil {
+		return false
+	}
+
+	var vout, verr = axlsign.VRFVerify(pub, msg, proof)
+	if verr != nil || vout != out {
+		return false
+	}
+
+	if _, err = axlsign.VRFVerify(other, msg, proof); err != axlsign.ErrInvalidProof {
+		return false
+	}
+	if _, err = axlsign.VRFVerify(pub, append([]uint8{0}, msg...), proof); err != axlsign.ErrInvalidProof {
+		return false
+	}
+	for _, i := range []int{0, 32, 64} {
+		var bad = proof
+		bad[i] ^= 1
+		if _, err = axlsign.VRFVerify(pub, msg, bad); err != axlsign.ErrInvalidProof {
+			return false
+		}
+	}
+
+	// s + L still satisfies the equations, but is not reduced.
+	var big = vrfAddL(proof)
+	if big[95]&0xe0 != 0 {
+		return true
+	}
+	_, err = axlsign.VRFVerify(pub, msg, big)
+	return err == axlsign.ErrInvalidProof
+}
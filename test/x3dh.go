@@ -0,0 +1,40 @@
+package main
+
+import cryptorand "crypto/rand"
+import "curve25519-go/axlsign"
+import "curve25519-go/x3dh"
+
+// X3DH between alice and bob, with and without a one-time prekey.
+func x3dhTest(alice axlsign.PrivateKey, bob axlsign.PrivateKey) bool {
+	var info = []uint8("curve25519-go x3dh")
+	var store = x3dh.NewMemoryStore()
+
+	var _, spk, _ = axlsign.GenerateKeyDefault()
+	var _, opk, _ = axlsign.GenerateKeyDefault()
+	store.PutSignedPreKey(1, spk)
+	store.PutOneTimePreKey(7, opk)
+
+	var bundle = x3dh.NewPreKeyBundle(bob, x3dh.PreKey{ID: 1, PublicKey: spk.Public()},
+		&x3dh.PreKey{ID: 7, PublicKey: opk.Public()})
+
+	for i := 0; i < 2; i++ {
+		var msg, sa, err = x3dh.Initiate(cryptorand.Reader, alice, bundle, info)
+		if err != nil {
+			return false
+		}
+		var sb, rerr = x3dh.Respond(store, bob, msg, info)
+		if rerr != nil || sa.SharedKey != sb.SharedKey || string(sa.AssociatedData) != string(sb.AssociatedData) {
+			return false
+		}
+
+		// la prekey de un solo uso ya no está; el segundo intercambio va sin ella
+		if _, rerr = x3dh.Respond(store, bob, msg, info); (rerr == x3dh.ErrPreKeyNotFound) != (i == 0) {
+			return false
+		}
+		bundle.OneTimePreKey = nil
+	}
+
+	bundle.SignedPreKeySignature[0] ^= 1
+	var _, _, err = x3dh.Initiate(cryptorand.Reader, alice, bundle, info)
+	return err == x3dh.ErrInvalidSignature
+}
@@ -0,0 +1,43 @@
+package main
+
+import "bytes"
+import "curve25519-go/axlsign"
+
+// xeddsa_slow_test in Signal's reference code: private key 0...0 with
+// byte 8 = 189, a 200-byte zero message and a zero Z.
+const xeddsaSignature = "11c7f3e6c4df9e8a5150e1db3b30f92de3a3b3aa438656545fa7390f4bcc7bb26c431d9e90643e4f0eaa0e9c557766fa69ada576d63dcaf2ac326c11d0b97702"
+
+// A signed prekey signature from libsignal's session tests: Alice's
+// identity key pair signs her serialized ephemeral key (0x05 || key).
+// libsignal keeps the sign bit of A in the signature, so it is checked
+// with Verify rather than VerifyXEdDSA.
+const (
+	libsignalPriv = "c097248412e58bf05df487968205132794178e367637f5818f81e0e6ce73e865"
+	libsignalPub  = "ab7e717d4a163b7d9a1d8071dfe9dcf8cdcd1cea3339b6356be84d887e322c64"
+	libsignalMsg  = "05edce9d9c415ca78cb7252e72c2c4a554d3eb29485a0e1d503118d1a82d99fb4a"
+	libsignalSig  = "5de88ca9a89b4a115da79109c67c9c7464a3e4180274f1cb8c63c2984e286dfbede82deb9dcd9fae0bfbb821569b3d9001bd8130cd11d486cef047bd60b86e88"
+)
+
+// Checks the XEdDSA known answer with a fixed Z, and a libsignal signature.
+func xeddsaTest() bool {
+	var priv axlsign.PrivateKey
+	priv[8] = 189
+	var msg = make([]uint8, 200)
+	var z = make([]uint8, 64)
+
+	var sig, err = axlsign.SignXEdDSA(bytes.NewReader(z), priv, msg)
+	if err != nil || !bytes.Equal(sig[:], unhex(xeddsaSignature)) {
+		return false
+	}
+	if !axlsign.VerifyXEdDSA(priv.Public(), msg, sig) {
+		return false
+	}
+
+	var lpriv axlsign.PrivateKey
+	copy(lpriv[:], unhex(libsignalPriv))
+	var lpub = lpriv.Public()
+	if !bytes.Equal(lpub[:], unhex(libsignalPub)) {
+		return false
+	}
+	return axlsign.Verify(lpub[:], unhex(libsignalMsg), unhex(libsignalSig)) == 1
+}
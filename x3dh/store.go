@@ -0,0 +1,69 @@
+// Prekey storage.
+
+package x3dh
+
+import "sync"
+import "curve25519-go/axlsign"
+
+// PreKeyStore holds the private prekeys of a responder.
+type PreKeyStore interface {
+  // SignedPreKey returns the signed prekey with the given identifier.
+  SignedPreKey(id uint32) (axlsign.PrivateKey, error)
+
+  // TakeOneTimePreKey returns the one-time prekey with the given
+  // identifier and deletes it, so it is never used twice.
+  TakeOneTimePreKey(id uint32) (axlsign.PrivateKey, error)
+}
+
+// MemoryStore is a PreKeyStore kept in memory. It is safe for concurrent
+// use.
+type MemoryStore struct {
+  mu sync.Mutex
+  signed map[uint32]axlsign.PrivateKey
+  oneTime map[uint32]axlsign.PrivateKey
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+  return &MemoryStore{
+    signed: make(map[uint32]axlsign.PrivateKey),
+    oneTime: make(map[uint32]axlsign.PrivateKey),
+  }
+}
+
+// PutSignedPreKey stores a signed prekey, replacing any with the same id.
+func (s *MemoryStore) PutSignedPreKey(id uint32, priv axlsign.PrivateKey) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+  s.signed[id] = priv
+}
+
+// PutOneTimePreKey stores a one-time prekey, replacing any with the same id.
+func (s *MemoryStore) PutOneTimePreKey(id uint32, priv axlsign.PrivateKey) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+  s.oneTime[id] = priv
+}
+
+// SignedPreKey implements PreKeyStore.
+func (s *MemoryStore) SignedPreKey(id uint32) (axlsign.PrivateKey, error) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+  var priv, ok = s.signed[id]
+  if (!ok) {
+    return priv, ErrPreKeyNotFound
+  }
+  return priv, nil
+}
+
+// TakeOneTimePreKey implements PreKeyStore.
+func (s *MemoryStore) TakeOneTimePreKey(id uint32) (axlsign.PrivateKey, error) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+  var priv, ok = s.oneTime[id]
+  if (!ok) {
+    return priv, ErrPreKeyNotFound
+  }
+  delete(s.oneTime, id)
+  return priv, nil
+}
@@ -0,0 +1,190 @@
+// Package x3dh implements the X3DH key agreement protocol, as specified by
+// Signal: https://signal.org/docs/specifications/x3dh/
+//
+// Identity keys are axlsign key pairs, used both for X25519 and to sign
+// the signed prekey. With the initiator A and the responder B:
+//
+//   DH1 = DH(IK_A, SPK_B)
+//   DH2 = DH(EK_A, IK_B)
+//   DH3 = DH(EK_A, SPK_B)
+//   DH4 = DH(EK_A, OPK_B)    if B's bundle has a one-time prekey
+//   SK  = HKDF-SHA256(0xff * 32 || DH1 || DH2 || DH3 [|| DH4], info)
+//   AD  = Encode(IK_A) || Encode(IK_B)
+
+package x3dh
+
+import "crypto/sha256"
+import "errors"
+import "io"
+import "curve25519-go/axlsign"
+import "curve25519-go/internal/hkdf"
+
+var (
+  // ErrInvalidSignature is returned when the signed prekey signature of a
+  // bundle does not verify.
+  ErrInvalidSignature = errors.New("x3dh: invalid signed prekey signature")
+
+  // ErrPreKeyNotFound is returned when a prekey is not in the store.
+  ErrPreKeyNotFound = errors.New("x3dh: prekey not found")
+)
+
+// PreKey is a public prekey and its identifier.
+type PreKey struct {
+  ID uint32
+  PublicKey axlsign.PublicKey
+}
+
+// PreKeyBundle is the set of keys B publishes so that A can start a
+// session while B is offline.
+type PreKeyBundle struct {
+  IdentityKey axlsign.PublicKey
+  SignedPreKey PreKey
+  SignedPreKeySignature axlsign.Signature
+
+  // OneTimePreKey is nil if B has run out of one-time prekeys.
+  OneTimePreKey *PreKey
+}
+
+// InitialMessage is sent by A along with the first message of the session.
+type InitialMessage struct {
+  IdentityKey axlsign.PublicKey
+  EphemeralKey axlsign.PublicKey
+  SignedPreKeyID uint32
+
+  // OneTimePreKeyID is nil if no one-time prekey was used.
+  OneTimePreKeyID *uint32
+}
+
+// Session is the result of the key agreement: the shared secret key SK and
+// the associated data AD to bind into the first messages.
+type Session struct {
+  SharedKey [32]uint8
+  AssociatedData []uint8
+}
+
+// Encode(PK): the curve type byte 0x05 followed by the u-coordinate.
+func encode(pk axlsign.PublicKey) []uint8 {
+  return append([]uint8{ 0x05 }, pk[:]...)
+}
+
+// SignPreKey returns the signature of the prekey pub by the identity key.
+func SignPreKey(identity axlsign.PrivateKey, pub axlsign.PublicKey) axlsign.Signature {
+  return identity.Sign(encode(pub))
+}
+
+// NewPreKeyBundle returns the bundle for the identity key, its signed
+// prekey and an optional one-time prekey.
+func NewPreKeyBundle(identity axlsign.PrivateKey, signedPreKey PreKey, oneTimePreKey *PreKey) PreKeyBundle {
+  return PreKeyBundle{
+    IdentityKey: identity.Public(),
+    SignedPreKey: signedPreKey,
+    SignedPreKeySignature: SignPreKey(identity, signedPreKey.PublicKey),
+    OneTimePreKey: oneTimePreKey,
+  }
+}
+
+// Verify reports whether the signed prekey signature is valid.
+func (b *PreKeyBundle) Verify() bool {
+  return b.IdentityKey.Verify(encode(b.SignedPreKey.PublicKey), b.SignedPreKeySignature)
+}
+
+// Appends the checked X25519 output of priv and pub to km.
+func dh(km []uint8, priv axlsign.PrivateKey, pub axlsign.PublicKey) ([]uint8, error) {
+  var shared, err = axlsign.ECDH(priv[:], pub[:])
+  if (err != nil) {
+    return nil, err
+  }
+  return append(km, shared...), nil
+}
+
+// SK = KDF(DH1 || DH2 || DH3 [|| DH4])
+func kdf(km []uint8, info []uint8) [32]uint8 {
+  var sk [32]uint8
+  copy(sk[:], hkdf.Key(sha256.New, km, nil, info, 32))
+  return sk
+}
+
+// Initiate runs the initiator side of X3DH with A's identity key and B's
+// bundle. The ephemeral key is read from rand; info identifies the
+// application.
+func Initiate(rand io.Reader, identity axlsign.PrivateKey, bundle PreKeyBundle, info []uint8) (InitialMessage, Session, error) {
+  var msg InitialMessage
+  var session Session
+
+  if (!bundle.Verify()) {
+    return msg, session, ErrInvalidSignature
+  }
+
+  var _, ek, err = axlsign.GenerateKey(rand)
+  if (err != nil) {
+    return msg, session, err
+  }
+
+  var km = make([]uint8, 32, 32 * 5)
+  for i := 0; i < 32; i++ {
+    km[i] = 0xff
+  }
+  if km, err = dh(km, identity, bundle.SignedPreKey.PublicKey); err != nil {
+    return msg, session, err
+  }
+  if km, err = dh(km, ek, bundle.IdentityKey); err != nil {
+    return msg, session, err
+  }
+  if km, err = dh(km, ek, bundle.SignedPreKey.PublicKey); err != nil {
+    return msg, session, err
+  }
+  if (bundle.OneTimePreKey != nil) {
+    if km, err = dh(km, ek, bundle.OneTimePreKey.PublicKey); err != nil {
+      return msg, session, err
+    }
+    var id = bundle.OneTimePreKey.ID
+    msg.OneTimePreKeyID = &id
+  }
+
+  msg.IdentityKey = identity.Public()
+  msg.EphemeralKey = ek.Public()
+  msg.SignedPreKeyID = bundle.SignedPreKey.ID
+
+  session.SharedKey = kdf(km, info)
+  session.AssociatedData = append(encode(msg.IdentityKey), encode(bundle.IdentityKey)...)
+  return msg, session, nil
+}
+
+// Respond runs the responder side of X3DH for A's initial message, with
+// B's identity key and the prekeys in store. The one-time prekey, if any,
+// is removed from the store.
+func Respond(store PreKeyStore, identity axlsign.PrivateKey, msg InitialMessage, info []uint8) (Session, error) {
+  var session Session
+
+  var spk, err = store.SignedPreKey(msg.SignedPreKeyID)
+  if (err != nil) {
+    return session, err
+  }
+
+  var km = make([]uint8, 32, 32 * 5)
+  for i := 0; i < 32; i++ {
+    km[i] = 0xff
+  }
+  if km, err = dh(km, spk, msg.IdentityKey); err != nil {
+    return session, err
+  }
+  if km, err = dh(km, identity, msg.EphemeralKey); err != nil {
+    return session, err
+  }
+  if km, err = dh(km, spk, msg.EphemeralKey); err != nil {
+    return session, err
+  }
+  if (msg.OneTimePreKeyID != nil) {
+    var opk, err = store.TakeOneTimePreKey(*msg.OneTimePreKeyID)
+    if (err != nil) {
+      return session, err
+    }
+    if km, err = dh(km, opk, msg.EphemeralKey); err != nil {
+      return session, err
+    }
+  }
+
+  session.SharedKey = kdf(km, info)
+  session.AssociatedData = append(encode(msg.IdentityKey), encode(identity.Public())...)
+  return session, nil
+}